@@ -0,0 +1,293 @@
+// #############################################################################
+// # File: async_core.go                                                       #
+// # Project: zlog                                                             #
+// # Created Date: 2026/10/17 20:23:03                                         #
+// # Author: realjf                                                            #
+// # -----                                                                     #
+// # Last Modified: 2026/10/17 20:48:32                                        #
+// # Modified By: realjf                                                       #
+// # -----                                                                     #
+// #                                                                           #
+// #############################################################################
+package zlog
+
+import (
+	"bufio"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	asyncQueueSize     = 8192
+	asyncBatchSize     = 128
+	asyncFlushInterval = time.Second
+	asyncBufferSize    = 256 * 1024
+	asyncBlock         = "block"
+	asyncDrop          = "drop"
+)
+
+type AsyncConfig struct {
+	QueueSize      int      `yaml:"queue_size" json:"queue_size"`             // 队列长度，默认8192
+	FlushInterval  string   `yaml:"flush_interval" json:"flush_interval"`     // 缓冲区最长刷新间隔，默认1s
+	BatchSize      int      `yaml:"batch_size" json:"batch_size"`             // 缓冲区累计多少条日志后刷新，默认128
+	OnFull         string   `yaml:"on_full" json:"on_full"`                   // 队列满时的策略 block|drop，默认block
+	NeverDropLevel LogLevel `yaml:"never_drop_level" json:"never_drop_level"` // 不会被丢弃的最低级别，默认error
+}
+
+// 异步写入队列，由日志记录器的所有core共享，单个协程负责写入和刷新缓冲区
+type asyncQueue struct {
+	entries       chan asyncEntry
+	batchSize     int
+	flushInterval time.Duration
+	dropOnFull    bool
+	neverDrop     zapcore.Level
+	buffers       []*bufferedWriteSyncer
+	dropped       atomic.Uint64
+
+	lock      sync.RWMutex // 保护closed，关闭后日志在调用方协程中同步写入
+	closed    bool
+	writeLock sync.Mutex // 关闭后同步写入时使用
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+type asyncEntry struct {
+	buffer *bufferedWriteSyncer // 写入的缓冲区，nil表示同步请求
+	data   []byte               // 编码后的日志
+	result chan error           // 同步请求通过result返回结果
+}
+
+// 异步写入的WriteSyncer，Write复制编码后的日志并入队，由写入协程写入缓冲区
+type queuedWriteSyncer struct {
+	queue     *asyncQueue
+	buffer    *bufferedWriteSyncer
+	droppable bool // 队列满时是否可以丢弃
+}
+
+// 带缓冲的WriteSyncer，只在asyncQueue的写入协程（或关闭后持有writeLock时）中使用
+type bufferedWriteSyncer struct {
+	ws  zapcore.WriteSyncer
+	buf *bufio.Writer
+}
+
+// =========================================================== 构造方法 ===========================================================
+
+func newAsyncQueue(config *AsyncConfig) (*asyncQueue, error) {
+	flushInterval, err := parseAsyncConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	q := &asyncQueue{
+		batchSize:     config.BatchSize,
+		flushInterval: flushInterval,
+		dropOnFull:    config.OnFull == asyncDrop,
+		neverDrop:     zapcore.ErrorLevel,
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+	if q.batchSize <= 0 {
+		q.batchSize = asyncBatchSize
+	}
+	queueSize := config.QueueSize
+	if queueSize <= 0 {
+		queueSize = asyncQueueSize
+	}
+	q.entries = make(chan asyncEntry, queueSize)
+	if config.NeverDropLevel != "" {
+		q.neverDrop = config.NeverDropLevel.ZapLevel()
+	}
+	go q.run()
+	return q, nil
+}
+
+func parseAsyncConfig(config *AsyncConfig) (time.Duration, error) {
+	if config.QueueSize < 0 || config.BatchSize < 0 {
+		return 0, errors.WithMessage(ErrInvalidValue, "async.queue_size/async.batch_size不能为负数")
+	}
+	if config.OnFull != "" && config.OnFull != asyncBlock && config.OnFull != asyncDrop {
+		return 0, errors.WithMessagef(ErrInvalidValue, "async.on_full取值非法：%s", config.OnFull)
+	}
+	if config.NeverDropLevel != "" && !config.NeverDropLevel.valid() {
+		return 0, errors.WithMessagef(ErrInvalidLevel, "async.never_drop_level取值非法：%s", config.NeverDropLevel)
+	}
+	if config.FlushInterval == "" {
+		return asyncFlushInterval, nil
+	}
+	flushInterval, err := time.ParseDuration(config.FlushInterval)
+	if err != nil || flushInterval <= 0 {
+		return 0, errors.WithMessagef(ErrInvalidValue, "async.flush_interval取值非法：%s", config.FlushInterval)
+	}
+	return flushInterval, nil
+}
+
+// 创建异步写入的core，日志在调用方协程中编码，编码结果入队后由写入协程写入ws的缓冲区
+//
+// 队列满时丢弃日志的策略按级别区分，低于NeverDropLevel的日志由可丢弃的写入器写入
+func (q *asyncQueue) core(enc zapcore.Encoder, ws zapcore.WriteSyncer, level zapcore.LevelEnabler) zapcore.Core {
+	b := &bufferedWriteSyncer{ws: ws, buf: bufio.NewWriterSize(ws, asyncBufferSize)}
+	q.buffers = append(q.buffers, b)
+	if !q.dropOnFull {
+		return zapcore.NewCore(enc, &queuedWriteSyncer{queue: q, buffer: b}, level)
+	}
+	droppable := zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
+		return lvl < q.neverDrop && level.Enabled(lvl)
+	})
+	blocking := zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
+		return lvl >= q.neverDrop && level.Enabled(lvl)
+	})
+	return zapcore.NewTee(
+		zapcore.NewCore(enc.Clone(), &queuedWriteSyncer{queue: q, buffer: b, droppable: true}, droppable),
+		zapcore.NewCore(enc, &queuedWriteSyncer{queue: q, buffer: b}, blocking),
+	)
+}
+
+// =========================================================== 接口方法 ===========================================================
+
+func (w *queuedWriteSyncer) Write(p []byte) (int, error) {
+	// p在Write返回后会被zap复用，入队前复制一份
+	e := asyncEntry{buffer: w.buffer, data: append([]byte(nil), p...)}
+	if err := w.queue.push(e, w.droppable); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// DPanic及以上级别的日志写入后zapcore会调用Sync，等待队列中的日志全部写入
+func (w *queuedWriteSyncer) Sync() error {
+	return w.queue.sync()
+}
+
+func (b *bufferedWriteSyncer) Write(p []byte) (int, error) {
+	return b.buf.Write(p)
+}
+
+func (b *bufferedWriteSyncer) Sync() error {
+	return multierr.Append(b.buf.Flush(), b.ws.Sync())
+}
+
+// 写入并同步所有缓冲区
+func (q *asyncQueue) sync() error {
+	return q.push(asyncEntry{result: make(chan error, 1)}, false)
+}
+
+// 被丢弃的日志条数
+func (q *asyncQueue) Dropped() uint64 {
+	return q.dropped.Load()
+}
+
+// 写入队列中剩余的日志并停止写入协程，之后的日志在调用方协程中同步写入
+func (q *asyncQueue) Close() error {
+	q.lock.Lock()
+	if q.closed {
+		q.lock.Unlock()
+		return nil
+	}
+	q.closed = true
+	q.lock.Unlock()
+
+	close(q.stop)
+	<-q.done
+	return q.syncBuffers()
+}
+
+// =========================================================== 私有方法 ===========================================================
+
+func (q *asyncQueue) push(e asyncEntry, droppable bool) error {
+	q.lock.RLock()
+	if q.closed {
+		q.lock.RUnlock()
+
+		q.writeLock.Lock()
+		defer q.writeLock.Unlock()
+		if err := q.write(e); err != nil || e.buffer == nil {
+			return err
+		}
+		return e.buffer.buf.Flush()
+	}
+
+	if droppable {
+		select {
+		case q.entries <- e:
+		default:
+			q.dropped.Add(1)
+		}
+		q.lock.RUnlock()
+		return nil
+	}
+	q.entries <- e
+	q.lock.RUnlock()
+
+	if e.result != nil {
+		return <-e.result
+	}
+	return nil
+}
+
+func (q *asyncQueue) run() {
+	defer close(q.done)
+
+	ticker := time.NewTicker(q.flushInterval)
+	defer ticker.Stop()
+
+	pending := 0
+	for {
+		select {
+		case e := <-q.entries:
+			_ = q.write(e)
+			if e.result != nil {
+				pending = 0
+				continue
+			}
+			if pending++; pending >= q.batchSize {
+				q.flushBuffers()
+				pending = 0
+			}
+		case <-ticker.C:
+			if pending > 0 {
+				q.flushBuffers()
+				pending = 0
+			}
+		case <-q.stop:
+			for {
+				select {
+				case e := <-q.entries:
+					_ = q.write(e)
+				default:
+					q.flushBuffers()
+					return
+				}
+			}
+		}
+	}
+}
+
+// 写入单条日志到缓冲区，同步请求时刷新并同步所有缓冲区
+func (q *asyncQueue) write(e asyncEntry) error {
+	if e.buffer == nil {
+		err := q.syncBuffers()
+		e.result <- err
+		return err
+	}
+	_, err := e.buffer.Write(e.data)
+	return err
+}
+
+func (q *asyncQueue) flushBuffers() {
+	for _, b := range q.buffers {
+		_ = b.buf.Flush()
+	}
+}
+
+func (q *asyncQueue) syncBuffers() error {
+	var errs error
+	for _, b := range q.buffers {
+		errs = multierr.Append(errs, b.Sync())
+	}
+	return errs
+}
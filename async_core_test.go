@@ -0,0 +1,164 @@
+// #############################################################################
+// # File: async_core_test.go                                                  #
+// # Project: zlog                                                             #
+// # Created Date: 2026/10/17 20:24:10                                         #
+// # Author: realjf                                                            #
+// # -----                                                                     #
+// # Last Modified: 2026/10/17 20:48:32                                        #
+// # Modified By: realjf                                                       #
+// # -----                                                                     #
+// #                                                                           #
+// #############################################################################
+package zlog_test
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/realjf/zlog"
+)
+
+func countLines(t *testing.T, path, substr string) int {
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	n := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if strings.Contains(scanner.Text(), substr) {
+			n++
+		}
+	}
+	return n
+}
+
+func TestAsyncBlock(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "zlog.log")
+	z := zlog.NewZLog([]*zlog.ZLogConfig{
+		{
+			LogMode:  "file",
+			Encoding: "json",
+			LogFile:  logFile,
+			Name:     "zlog",
+			Async: &zlog.AsyncConfig{
+				QueueSize: 16,
+				BatchSize: 8,
+			},
+		},
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				z.Infof("async %d", j)
+			}
+		}()
+	}
+	wg.Wait()
+	if err := z.GetZCore("zlog").Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := countLines(t, logFile, "async"); n != 1000 {
+		t.Fatalf("expected 1000 lines, got %d", n)
+	}
+	if dropped := z.Stats()["zlog"].Dropped; dropped != 0 {
+		t.Fatalf("expected no dropped entries, got %d", dropped)
+	}
+}
+
+func TestAsyncDrop(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "zlog.log")
+	z := zlog.NewZLog([]*zlog.ZLogConfig{
+		{
+			LogMode:  "file",
+			Encoding: "json",
+			LogFile:  logFile,
+			Name:     "zlog",
+			Async: &zlog.AsyncConfig{
+				QueueSize:      1,
+				OnFull:         "drop",
+				NeverDropLevel: "warn",
+			},
+		},
+	})
+
+	const total = 10000
+	for i := 0; i < total; i++ {
+		z.Infof("info %d", i)
+		if i%100 == 0 {
+			z.Warnf("warn %d", i)
+		}
+	}
+	if err := z.GetZCore("zlog").Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	dropped := z.Stats()["zlog"].Dropped
+	t.Logf("dropped %d of %d", dropped, total)
+	if n := countLines(t, logFile, `"info`); uint64(n)+dropped != total {
+		t.Fatalf("written %d + dropped %d != %d", n, dropped, total)
+	}
+	if n := countLines(t, logFile, `"warn`); n != total/100 {
+		t.Fatalf("expected %d warn lines, got %d", total/100, n)
+	}
+}
+
+func TestAsyncInvalid(t *testing.T) {
+	_, err := zlog.NewZLogE([]*zlog.ZLogConfig{
+		{
+			Async: &zlog.AsyncConfig{
+				FlushInterval: "soon",
+				OnFull:        "ignore",
+			},
+		},
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	t.Log(err)
+}
+
+func TestAsyncFieldMutation(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "zlog.log")
+	z := zlog.NewZLog([]*zlog.ZLogConfig{
+		{
+			LogMode:  "file",
+			Encoding: "json",
+			LogFile:  logFile,
+			Name:     "zlog",
+			Async:    &zlog.AsyncConfig{},
+		},
+	})
+
+	// 日志在调用方协程中编码，返回后修改字段不影响已记录的日志，-race下也不会产生数据竞争
+	m := map[string]int{"x": 1}
+	for i := 0; i < 100; i++ {
+		z.Infow("mutate", zap.Any("m", m))
+		m["x"] = 2
+		z.Infow("mutate", zap.Any("m", m))
+		m["x"] = 1
+	}
+	if err := z.GetZCore("zlog").Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := countLines(t, logFile, `"m":{"x":1}`); n != 100 {
+		t.Fatalf("expected 100 lines with x=1, got %d", n)
+	}
+	if n := countLines(t, logFile, `"m":{"x":2}`); n != 100 {
+		t.Fatalf("expected 100 lines with x=2, got %d", n)
+	}
+}
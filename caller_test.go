@@ -0,0 +1,130 @@
+// #############################################################################
+// # File: caller_test.go                                                      #
+// # Project: zlog                                                             #
+// # Created Date: 2026/10/17 20:37:51                                         #
+// # Author: realjf                                                            #
+// # -----                                                                     #
+// # Last Modified: 2026/10/17 20:57:46                                        #
+// # Modified By: realjf                                                       #
+// # -----                                                                     #
+// #                                                                           #
+// #############################################################################
+package zlog_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/realjf/zlog"
+	"github.com/realjf/zlog/trace"
+)
+
+func newCallerZLog(t *testing.T, encoding string, skip int) (zlog.IZLog, string) {
+	logFile := filepath.Join(t.TempDir(), "zlog.log")
+	return zlog.NewZLog([]*zlog.ZLogConfig{
+		{
+			LogMode:        "file",
+			Encoding:       "json",
+			LogFile:        logFile,
+			Name:           "zlog",
+			CallerEncoding: encoding,
+			CallerSkip:     skip,
+			Async:          &zlog.AsyncConfig{},
+		},
+	}), logFile
+}
+
+func TestCaller(t *testing.T) {
+	z, logFile := newCallerZLog(t, "", 0)
+	defer z.Close()
+	defer zlog.SetFatalHook(zlog.FatalPanic)()
+	ctx := trace.WithTraceContext(context.Background(), trace.NewTraceContext())
+
+	z.Info("Info")
+	z.Infof("%s", "Infof")
+	z.Infow("Infow")
+	z.InfoWithTrace(ctx, "InfoWithTrace")
+	z.InfofWithTrace(ctx, "%s", "InfofWithTrace")
+	z.InfowWithTrace(ctx, "InfowWithTrace")
+	z.Ctx(ctx).Warn("Ctx")
+	z.WithName("zlog").WithPrefix("[p]").With(zap.Int("k", 1)).Error("Derived")
+	z.DPanic("DPanic")
+	expectPanic(t, true, func() { z.Panic("Panic") })
+	expectPanic(t, true, func() { z.Fatalf("Fatalf") })
+	if err := z.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	entries := readEntries(t, logFile)
+	if len(entries) != 11 {
+		t.Fatalf("expected 11 entries, got %d", len(entries))
+	}
+	for _, entry := range entries {
+		if file, _, _ := strings.Cut(entry.Caller, ":"); strings.Count(file, "/") != 1 || filepath.Base(file) != "caller_test.go" {
+			t.Errorf("%s: caller = %q, want caller_test.go", entry.Msg, entry.Caller)
+		}
+		if entry.Stacktrace != "" && !strings.HasPrefix(entry.Stacktrace, "github.com/realjf/zlog_test.TestCaller") {
+			t.Errorf("%s: stacktrace starts in %q", entry.Msg, strings.SplitN(entry.Stacktrace, "\n", 2)[0])
+		}
+	}
+}
+
+// 再次封装zlog的库通过CallerSkip跳过自己的栈帧
+func wrappedInfo(z zlog.IZLog, msg string) {
+	z.Info(msg)
+}
+
+func TestCallerEncoding(t *testing.T) {
+	cases := map[string]string{
+		"full":     "/caller_test.go:",
+		"function": "github.com/realjf/zlog_test.TestCallerEncoding",
+	}
+	for encoding, want := range cases {
+		z, logFile := newCallerZLog(t, encoding, 0)
+		z.Info(encoding)
+		z.Close()
+		if caller := readEntries(t, logFile)[0].Caller; !strings.Contains(caller, want) {
+			t.Errorf("%s: caller = %q, want %q", encoding, caller, want)
+		}
+	}
+
+	z, logFile := newCallerZLog(t, "function", 1)
+	wrappedInfo(z, "skip")
+	z.Close()
+	if caller := readEntries(t, logFile)[0].Caller; caller != "github.com/realjf/zlog_test.TestCallerEncoding" {
+		t.Errorf("CallerSkip: caller = %q", caller)
+	}
+}
+
+// options.caller_skip作为未设置caller_skip的日志记录器的默认值，不影响GetZCore返回的记录器
+func TestCallerSkipOption(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "zlog.yaml")
+	logFile := filepath.Join(dir, "zlog.log")
+	content := "loggers:\n  - log_mode: file\n    encoding: json\n    caller_encoding: function\n    log_file: " + logFile + "\noptions:\n  caller_skip: 1\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	z, err := zlog.NewZLogFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wrappedInfo(z, "skip")
+	z.GetZCore("").Info("raw")
+	z.Close()
+
+	entries := readEntries(t, logFile)
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	for _, entry := range entries {
+		if entry.Caller != "github.com/realjf/zlog_test.TestCallerSkipOption" {
+			t.Errorf("%s: caller = %q", entry.Msg, entry.Caller)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+// #############################################################################
+// # File: config.go                                                           #
+// # Project: zlog                                                             #
+// # Created Date: 2026/10/17 20:09:29                                         #
+// # Author: realjf                                                            #
+// # -----                                                                     #
+// # Last Modified: 2026/10/17 20:57:46                                        #
+// # Modified By: realjf                                                       #
+// # -----                                                                     #
+// #                                                                           #
+// #############################################################################
+package zlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	configFormatYaml = "yaml"
+	configFormatJson = "json"
+)
+
+// =========================================================== 结构体 ===========================================================
+
+// 日志配置文件的顶层结构
+type ZLogFileConfig struct {
+	Loggers []*ZLogConfig `yaml:"loggers" json:"loggers"` // 日志记录器列表
+	Options *ZapOptions   `yaml:"options" json:"options"` // zap选项
+}
+
+type ZapOptions struct {
+	Development   bool              `yaml:"development" json:"development"`       // 开发模式，DPanic级别会触发panic
+	CallerSkip    int               `yaml:"caller_skip" json:"caller_skip"`       // 未设置caller_skip的日志记录器使用的ZLogConfig.CallerSkip
+	AddStacktrace LogLevel          `yaml:"add_stacktrace" json:"add_stacktrace"` // 未设置stacktrace_level的日志记录器使用的ZLogConfig.StacktraceLevel
+	Fields        map[string]string `yaml:"fields" json:"fields"`                 // 附加到每条日志的固定字段
+}
+
+// =========================================================== 构造方法 ===========================================================
+
+// 从yaml/json配置文件初始化全局日志
+func InitZLogFromFile(path string, options ...zap.Option) error {
+	z, err := newZLogFromFile(path, options...)
+	if err != nil {
+		return err
+	}
+	localZLog = z
+	return nil
+}
+
+func NewZLogFromFile(path string, options ...zap.Option) (IZLog, error) {
+	return newZLogFromFile(path, options...)
+}
+
+func newZLogFromFile(path string, options ...zap.Option) (*zLog, error) {
+	fileConfig, err := LoadConfigFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := ApplyEnvOverrides(fileConfig.Loggers); err != nil {
+		return nil, err
+	}
+	if err := ValidateConfigs(fileConfig.Loggers); err != nil {
+		return nil, err
+	}
+	zapOptions, err := fileConfig.Options.Build()
+	if err != nil {
+		return nil, err
+	}
+	if options := fileConfig.Options; options != nil {
+		for _, config := range fileConfig.Loggers {
+			if options.Development {
+				config.Development = true
+			}
+			if config.CallerSkip == 0 {
+				config.CallerSkip = options.CallerSkip
+			}
+			if config.StacktraceLevel == "" {
+				config.StacktraceLevel = options.AddStacktrace
+			}
+		}
+	}
+	return newZLog(fileConfig.Loggers, append(zapOptions, options...)...)
+}
+
+// =========================================================== 配置加载 ===========================================================
+
+// 根据文件扩展名(.yaml/.yml/.json)读取日志配置
+func LoadConfigFile(path string) (*ZLogFileConfig, error) {
+	var format string
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		format = configFormatYaml
+	case ".json":
+		format = configFormatJson
+	default:
+		return nil, errors.Errorf("不支持的日志配置文件格式：%s", path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "读取日志配置文件[%s]失败", path)
+	}
+	fileConfig, err := LoadConfig(data, format)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "解析日志配置文件[%s]失败", path)
+	}
+	return fileConfig, nil
+}
+
+// 解析yaml/json格式的日志配置，未知字段和非法取值均返回错误
+func LoadConfig(data []byte, format string) (*ZLogFileConfig, error) {
+	fileConfig := &ZLogFileConfig{}
+	switch format {
+	case configFormatYaml:
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(fileConfig); err != nil {
+			if err == io.EOF {
+				return nil, ErrNoConfig
+			}
+			return nil, errors.WithStack(err)
+		}
+	case configFormatJson:
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(fileConfig); err != nil {
+			if err == io.EOF {
+				return nil, ErrNoConfig
+			}
+			return nil, errors.WithStack(err)
+		}
+		if decoder.More() {
+			return nil, errors.New("日志配置包含多余内容")
+		}
+	default:
+		return nil, errors.Errorf("不支持的日志配置格式：%s", format)
+	}
+
+	if err := validateConfigs(fileConfig.Loggers, false); err != nil {
+		return nil, err
+	}
+	if _, err := fileConfig.Options.Build(); err != nil {
+		return nil, errors.WithMessage(err, "options")
+	}
+	return fileConfig, nil
+}
+
+// 转换为zap.Option列表，调用位置和堆栈级别由ZLogConfig.CallerSkip/StacktraceLevel控制，不在此转换
+func (o *ZapOptions) Build() ([]zap.Option, error) {
+	options := make([]zap.Option, 0)
+	if o == nil {
+		return options, nil
+	}
+	if o.Development {
+		options = append(options, zap.Development())
+	}
+	if o.CallerSkip < 0 {
+		return nil, errors.Errorf("caller_skip取值非法：%d", o.CallerSkip)
+	}
+	if o.AddStacktrace != "" && !o.AddStacktrace.valid() {
+		return nil, errors.Errorf("add_stacktrace取值非法：%s", o.AddStacktrace)
+	}
+	if len(o.Fields) > 0 {
+		keys := make([]string, 0, len(o.Fields))
+		for key := range o.Fields {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		fields := make([]zap.Field, 0, len(keys))
+		for _, key := range keys {
+			fields = append(fields, zap.String(key, o.Fields[key]))
+		}
+		options = append(options, zap.Fields(fields...))
+	}
+	return options, nil
+}
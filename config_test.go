@@ -0,0 +1,109 @@
+// #############################################################################
+// # File: config_test.go                                                      #
+// # Project: zlog                                                             #
+// # Created Date: 2026/10/17 20:09:47                                         #
+// # Author: realjf                                                            #
+// # -----                                                                     #
+// # Last Modified: 2026/10/17 20:57:46                                        #
+// # Modified By: realjf                                                       #
+// # -----                                                                     #
+// #                                                                           #
+// #############################################################################
+package zlog_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/realjf/zlog"
+)
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	yamlPath := writeConfigFile(t, "zlog.yaml", `
+loggers:
+  - name: zlog
+    level: info
+    log_mode: file|console
+    encoding: json
+    log_file: ./logs/zlog.log
+    default: true
+  - name: zlog2
+    log_file: ./logs/zlog2.log
+options:
+  caller_skip: 1
+  fields:
+    service: zlog
+`)
+	jsonPath := writeConfigFile(t, "zlog.json", `{
+  "loggers": [
+    {"name": "zlog", "level": "info", "log_mode": "file|console", "encoding": "json", "log_file": "./logs/zlog.log", "default": true},
+    {"name": "zlog2", "log_file": "./logs/zlog2.log"}
+  ],
+  "options": {"caller_skip": 1, "fields": {"service": "zlog"}}
+}`)
+
+	for _, path := range []string{yamlPath, jsonPath} {
+		fileConfig, err := zlog.LoadConfigFile(path)
+		if err != nil {
+			t.Fatalf("load %s: %v", path, err)
+		}
+		if len(fileConfig.Loggers) != 2 {
+			t.Fatalf("load %s: expected 2 loggers, got %d", path, len(fileConfig.Loggers))
+		}
+		if cfg := fileConfig.Loggers[0]; cfg.Name != "zlog" || cfg.Level != "info" || !cfg.Default || cfg.Encoding != "json" {
+			t.Fatalf("load %s: unexpected config %+v", path, cfg)
+		}
+		if fileConfig.Options == nil || fileConfig.Options.CallerSkip != 1 || fileConfig.Options.Fields["service"] != "zlog" {
+			t.Fatalf("load %s: unexpected options %+v", path, fileConfig.Options)
+		}
+	}
+}
+
+func TestLoadConfigFileInvalid(t *testing.T) {
+	cases := map[string]string{
+		"unknown.yaml":  "loggers:\n  - name: zlog\n    levle: info\n",
+		"unknown.json":  `{"loggers": [{"name": "zlog", "levle": "info"}]}`,
+		"level.yaml":    "loggers:\n  - name: zlog\n    level: verbose\n",
+		"mode.json":     `{"loggers": [{"name": "zlog", "log_mode": "syslog"}]}`,
+		"encoding.yaml": "loggers:\n  - name: zlog\n    encoding: xml\n",
+		"type.yaml":     "loggers:\n  - name: zlog\n    max_size: big\n",
+		"empty.yaml":    "",
+		"nologger.json": `{"options": {"caller_skip": 1}}`,
+		"options.yaml":  "loggers:\n  - name: zlog\noptions:\n  add_stacktrace: loud\n",
+		"zlog.toml":     "",
+	}
+	for name, content := range cases {
+		path := writeConfigFile(t, name, content)
+		if _, err := zlog.LoadConfigFile(path); err == nil {
+			t.Errorf("%s: expected error", name)
+		} else {
+			t.Logf("%s: %v", name, err)
+		}
+	}
+}
+
+func TestInitZLogFromFile(t *testing.T) {
+	dir := t.TempDir()
+	path := writeConfigFile(t, "zlog.yaml", `
+loggers:
+  - log_mode: file
+    encoding: json
+    log_file: `+filepath.Join(dir, "zlog.log")+`
+`)
+	if err := zlog.InitZLogFromFile(path); err != nil {
+		t.Fatal(err)
+	}
+	zlog.ZLog().Infof("hello %s", "realjf")
+	if _, err := os.Stat(filepath.Join(dir, "zlog.log")); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,107 @@
+// #############################################################################
+// # File: context.go                                                          #
+// # Project: zlog                                                             #
+// # Created Date: 2026/10/17 20:31:47                                         #
+// # Author: realjf                                                            #
+// # -----                                                                     #
+// # Last Modified: 2026/10/17 20:32:54                                        #
+// # Modified By: realjf                                                       #
+// # -----                                                                     #
+// #                                                                           #
+// #############################################################################
+package zlog
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/realjf/zlog/trace"
+)
+
+// 从ctx中提取日志字段，ctx中没有相应信息时返回nil
+type ContextExtractor func(ctx context.Context) []zapcore.Field
+
+var (
+	extractorLock sync.Mutex                         // 串行化注册
+	extractors    atomic.Pointer[[]ContextExtractor] // 写时复制，打印日志时无锁读取
+)
+
+// 注册ctx字段提取器，对Ctx(ctx)以及所有*WithTrace方法生效，通常在init或main中调用
+func RegisterContextExtractor(extractor ContextExtractor) {
+	if extractor == nil {
+		return
+	}
+
+	extractorLock.Lock()
+	defer extractorLock.Unlock()
+
+	var list []ContextExtractor
+	if old := extractors.Load(); old != nil {
+		list = append(list, *old...)
+	}
+	list = append(list, extractor)
+	extractors.Store(&list)
+}
+
+// 返回附加了ctx中日志字段的派生日志，所有日志方法均可使用：
+//
+//	zlog.ZLog().Ctx(ctx).Info("hello")
+func (z *zLog) Ctx(ctx context.Context) IZLog {
+	return z.withContext(ctx)
+}
+
+type zLogKey struct{}
+
+// 将日志保存到ctx中，通常由中间件保存附加了请求字段的日志
+func NewContext(ctx context.Context, logger IZLog) context.Context {
+	return context.WithValue(ctx, zLogKey{}, logger)
+}
+
+// 获取ctx中保存的日志，未保存时返回全局日志
+func FromContext(ctx context.Context) IZLog {
+	if ctx != nil {
+		if logger, ok := ctx.Value(zLogKey{}).(IZLog); ok && logger != nil {
+			return logger
+		}
+	}
+	return ZLog()
+}
+
+// =========================================================== 私有方法 ===========================================================
+
+func (z *zLog) withContext(ctx context.Context) *zLog {
+	if ctx == nil {
+		return z
+	}
+	fields := contextFields(ctx)
+	if len(fields) == 0 {
+		return z
+	}
+	return z.withFields(fields...)
+}
+
+// 从ctx中提取链路追踪信息以及已注册的提取器返回的字段
+func contextFields(ctx context.Context) []zapcore.Field {
+	var fields []zapcore.Field
+	if tc, ok := trace.FromContext(ctx); ok && tc != nil {
+		fields = traceFields(tc)
+	}
+	if list := extractors.Load(); list != nil {
+		for _, extractor := range *list {
+			fields = append(fields, extractor(ctx)...)
+		}
+	}
+	return fields
+}
+
+func traceFields(tc *trace.TraceContext) []zapcore.Field {
+	return []zapcore.Field{
+		zap.String("traceID", tc.TraceID),
+		zap.String("spanID", tc.SpanID),
+		zap.String("parentSpanID", tc.ParentSpanID),
+	}
+}
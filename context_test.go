@@ -0,0 +1,109 @@
+// #############################################################################
+// # File: context_test.go                                                     #
+// # Project: zlog                                                             #
+// # Created Date: 2026/10/17 20:32:01                                         #
+// # Author: realjf                                                            #
+// # -----                                                                     #
+// # Last Modified: 2026/10/17 20:49:02                                        #
+// # Modified By: realjf                                                       #
+// # -----                                                                     #
+// #                                                                           #
+// #############################################################################
+package zlog_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/realjf/zlog"
+	"github.com/realjf/zlog/trace"
+)
+
+func TestCtx(t *testing.T) {
+	z, dir := newDeriveZLog(t)
+	defer z.Close()
+	tc := trace.NewTraceContext()
+	ctx := trace.WithTraceContext(context.Background(), tc)
+
+	z.Ctx(ctx).Info("ctx info")
+	z.Ctx(ctx).Warnf("ctx %s", "warnf")
+	z.Ctx(ctx).Errorw("ctx errorw", "k", "v")
+	z.WithPrefix("[p]").Ctx(ctx).WithName("zlog").Info("ctx chained")
+	z.Ctx(context.Background()).Info("no trace")
+	if err := z.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	logFile := filepath.Join(dir, "zlog.log")
+	if n := countLines(t, logFile, `"traceID":"`+tc.TraceID+`"`); n != 4 {
+		t.Fatalf("expected 4 lines with traceID, got %d", n)
+	}
+	if n := countLines(t, logFile, "[p] ctx chained"); n != 1 {
+		t.Fatalf("expected prefix to compose with Ctx, got %d lines", n)
+	}
+	if n := countLines(t, logFile, "traceID"); n != 4 {
+		t.Fatalf("expected no traceID without trace context, got %d lines", n)
+	}
+}
+
+type tenantKey struct{}
+
+func TestRegisterContextExtractor(t *testing.T) {
+	zlog.RegisterContextExtractor(func(ctx context.Context) []zapcore.Field {
+		if tenant, ok := ctx.Value(tenantKey{}).(string); ok {
+			return []zapcore.Field{zap.String("tenant", tenant)}
+		}
+		return nil
+	})
+
+	z, dir := newDeriveZLog(t)
+	defer z.Close()
+	ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+	ctx = trace.WithTraceContext(ctx, trace.NewTraceContext())
+
+	z.Ctx(ctx).Info("extracted")
+	z.InfofWithTrace(ctx, "extracted %s", "legacy")
+	z.WithOptions(zlog.WithTrace(ctx)).Info("extracted option")
+	z.Ctx(context.Background()).Info("no tenant")
+	if err := z.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	logFile := filepath.Join(dir, "zlog.log")
+	if n := countLines(t, logFile, `"tenant":"acme"`); n != 3 {
+		t.Fatalf("expected 3 lines with tenant, got %d", n)
+	}
+	if n := countLines(t, logFile, `"tenant":`); n != 3 {
+		t.Fatalf("expected no tenant without value in ctx, got %d lines", n)
+	}
+}
+
+func TestNewContext(t *testing.T) {
+	if zlog.FromContext(context.Background()) != zlog.ZLog() {
+		t.Fatal("expected FromContext to fall back to ZLog()")
+	}
+
+	z, dir := newDeriveZLog(t)
+	defer z.Close()
+	tc := trace.NewTraceContext()
+	ctx := trace.WithTraceContext(context.Background(), tc)
+	ctx = zlog.NewContext(ctx, z.Ctx(ctx).With(zap.String("request_id", "r1")).WithPrefix("[req]"))
+
+	func(ctx context.Context) {
+		zlog.FromContext(ctx).Info("deep call")
+	}(ctx)
+	if err := z.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := countLines(t, filepath.Join(dir, "zlog.log"), `"msg":"[req] deep call","traceID":"`+tc.TraceID); n != 1 {
+		t.Fatalf("expected request scoped logger from context, got %d lines", n)
+	}
+	if n := countLines(t, filepath.Join(dir, "zlog.log"), `"request_id":"r1"`); n != 1 {
+		t.Fatalf("expected request_id field, got %d lines", n)
+	}
+}
@@ -0,0 +1,156 @@
+// #############################################################################
+// # File: env.go                                                              #
+// # Project: zlog                                                             #
+// # Created Date: 2026/10/17 20:10:23                                         #
+// # Author: realjf                                                            #
+// # -----                                                                     #
+// # Last Modified: 2026/10/17 20:34:12                                        #
+// # Modified By: realjf                                                       #
+// # -----                                                                     #
+// #                                                                           #
+// #############################################################################
+package zlog
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+)
+
+// 环境变量前缀
+//
+// 支持全局变量 ZLOG_<KEY> 以及按日志名称作用的 ZLOG_<NAME>_<KEY>，
+// 其中 NAME 为 ZLogConfig.Name 转大写并将非字母数字字符替换为下划线，如 "access-log" => ACCESS_LOG。
+//
+// 优先级（从高到低）：ZLOG_<NAME>_<KEY> > ZLOG_<KEY> > ZLogConfig中的值 > 默认值
+const envPrefix = "ZLOG"
+
+type envField struct {
+	key string
+	set func(config *ZLogConfig, value string) error
+}
+
+var envFields = []envField{
+	{"LEVEL", func(config *ZLogConfig, value string) error {
+		level, err := ParseLogLevel(value)
+		if err != nil {
+			return errors.Errorf("level取值非法：%s", value)
+		}
+		config.Level = level
+		return nil
+	}},
+	{"LOG_MODE", func(config *ZLogConfig, value string) error {
+		if !validLogMode(value) {
+			return errors.Errorf("log_mode取值非法：%s", value)
+		}
+		config.LogMode = value
+		return nil
+	}},
+	{"MAX_SIZE", envInt(func(config *ZLogConfig, v int) { config.MaxSize = v })},
+	{"MAX_AGE", envInt(func(config *ZLogConfig, v int) { config.MaxAge = v })},
+	{"MAX_BACKUPS", envInt(func(config *ZLogConfig, v int) { config.MaxBackups = v })},
+	{"COMPRESS", envBool(func(config *ZLogConfig, v bool) { config.Compress = v })},
+	{"ENCODING", func(config *ZLogConfig, value string) error {
+		if !validEncoding(value) {
+			return errors.Errorf("encoding取值非法：%s", value)
+		}
+		config.Encoding = value
+		return nil
+	}},
+	{"LOG_FILE", func(config *ZLogConfig, value string) error {
+		config.LogFile = value
+		return nil
+	}},
+	{"SERVICE", func(config *ZLogConfig, value string) error {
+		config.Service = value
+		return nil
+	}},
+	{"DEFAULT", envBool(func(config *ZLogConfig, v bool) { config.Default = v })},
+	{"ROTATE_INTERVAL", func(config *ZLogConfig, value string) error {
+		if _, err := parseRotateInterval(value); err != nil {
+			return err
+		}
+		config.RotateInterval = value
+		return nil
+	}},
+	{"ROTATE_AT", func(config *ZLogConfig, value string) error {
+		if _, err := parseRotateAt(value); err != nil {
+			return err
+		}
+		config.RotateAt = value
+		return nil
+	}},
+}
+
+// 使用环境变量覆盖日志配置，非法的环境变量会被忽略并汇总为错误返回
+func ApplyEnvOverrides(configs []*ZLogConfig) error {
+	var errs error
+	for _, config := range configs {
+		if config == nil {
+			continue
+		}
+		for _, field := range envFields {
+			name, value, ok := lookupEnv(config.Name, field.key)
+			if !ok {
+				continue
+			}
+			if err := field.set(config, value); err != nil {
+				errs = multierr.Append(errs, errors.WithMessagef(err, "环境变量%s", name))
+			}
+		}
+	}
+	return errs
+}
+
+func applyEnvOverrides(configs []*ZLogConfig) {
+	if err := ApplyEnvOverrides(configs); err != nil {
+		log.Printf("忽略非法的日志环境变量：%v\n", err)
+	}
+}
+
+func lookupEnv(loggerName, key string) (string, string, bool) {
+	if loggerName != "" {
+		name := envPrefix + "_" + envName(loggerName) + "_" + key
+		if value, ok := os.LookupEnv(name); ok {
+			return name, value, true
+		}
+	}
+	name := envPrefix + "_" + key
+	value, ok := os.LookupEnv(name)
+	return name, value, ok
+}
+
+func envName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, strings.ToUpper(name))
+}
+
+func envInt(set func(config *ZLogConfig, v int)) func(config *ZLogConfig, value string) error {
+	return func(config *ZLogConfig, value string) error {
+		v, err := strconv.Atoi(value)
+		if err != nil || v < 0 {
+			return errors.Errorf("取值非法：%s", value)
+		}
+		set(config, v)
+		return nil
+	}
+}
+
+func envBool(set func(config *ZLogConfig, v bool)) func(config *ZLogConfig, value string) error {
+	return func(config *ZLogConfig, value string) error {
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return errors.Errorf("取值非法：%s", value)
+		}
+		set(config, v)
+		return nil
+	}
+}
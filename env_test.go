@@ -0,0 +1,87 @@
+// #############################################################################
+// # File: env_test.go                                                         #
+// # Project: zlog                                                             #
+// # Created Date: 2026/10/17 20:10:31                                         #
+// # Author: realjf                                                            #
+// # -----                                                                     #
+// # Last Modified: 2026/10/17 20:54:52                                        #
+// # Modified By: realjf                                                       #
+// # -----                                                                     #
+// #                                                                           #
+// #############################################################################
+package zlog_test
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/realjf/zlog"
+)
+
+func TestApplyEnvOverrides(t *testing.T) {
+	t.Setenv("ZLOG_LEVEL", "warn")
+	t.Setenv("ZLOG_ENCODING", "json")
+	t.Setenv("ZLOG_ACCESS_LOG_LEVEL", "error")
+	t.Setenv("ZLOG_ACCESS_LOG_LOG_FILE", "./logs/access.log")
+	t.Setenv("ZLOG_ACCESS_LOG_MAX_SIZE", "100")
+	t.Setenv("ZLOG_ACCESS_LOG_COMPRESS", "false")
+
+	configs := []*zlog.ZLogConfig{
+		{
+			Name:     "zlog",
+			Level:    "debug",
+			Encoding: "console",
+			LogFile:  "./logs/zlog.log",
+		},
+		{
+			Name:     "access-log",
+			Level:    "info",
+			Compress: true,
+		},
+	}
+	if err := zlog.ApplyEnvOverrides(configs); err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg := configs[0]; cfg.Level != "warn" || cfg.Encoding != "json" || cfg.LogFile != "./logs/zlog.log" {
+		t.Fatalf("unexpected config %+v", cfg)
+	}
+	if cfg := configs[1]; cfg.Level != "error" || cfg.Encoding != "json" || cfg.LogFile != "./logs/access.log" || cfg.MaxSize != 100 || cfg.Compress {
+		t.Fatalf("unexpected config %+v", cfg)
+	}
+}
+
+func TestApplyEnvOverridesInvalid(t *testing.T) {
+	t.Setenv("ZLOG_LEVEL", "verbose")
+	t.Setenv("ZLOG_ZLOG_MAX_AGE", "-1")
+	t.Setenv("ZLOG_ENCODING", "json")
+
+	configs := []*zlog.ZLogConfig{
+		{
+			Name:   "zlog",
+			Level:  "info",
+			MaxAge: 7,
+		},
+	}
+	err := zlog.ApplyEnvOverrides(configs)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	t.Log(err)
+	if cfg := configs[0]; cfg.Level != "info" || cfg.MaxAge != 7 || cfg.Encoding != "json" {
+		t.Fatalf("unexpected config %+v", cfg)
+	}
+}
+
+// 导入包时不读取环境变量，不可写的日志文件不会导致导入时panic
+func TestInitIgnoresEnv(t *testing.T) {
+	if os.Getenv("ZLOG_TEST_INIT") == "1" {
+		return
+	}
+	cmd := exec.Command(os.Args[0], "-test.run=^TestInitIgnoresEnv$")
+	cmd.Env = append(os.Environ(), "ZLOG_TEST_INIT=1", "ZLOG_LOG_MODE=file", "ZLOG_LOG_FILE=/proc/nope/x.log")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("import with env overrides failed: %v\n%s", err, out)
+	}
+}
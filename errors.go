@@ -0,0 +1,91 @@
+// #############################################################################
+// # File: errors.go                                                           #
+// # Project: zlog                                                             #
+// # Created Date: 2026/10/17 20:11:08                                         #
+// # Author: realjf                                                            #
+// # -----                                                                     #
+// # Last Modified: 2026/10/17 20:15:02                                        #
+// # Modified By: realjf                                                       #
+// # -----                                                                     #
+// #                                                                           #
+// #############################################################################
+package zlog
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrNoConfig        = errors.New("日志配置为空")
+	ErrNilConfig       = errors.New("日志配置项为nil")
+	ErrInvalidLevel    = errors.New("日志级别非法")
+	ErrInvalidLogMode  = errors.New("日志模式非法")
+	ErrInvalidEncoding = errors.New("日志编码非法")
+	ErrInvalidValue    = errors.New("配置取值非法")
+	ErrDuplicateName   = errors.New("日志名称重复")
+	ErrUnwritablePath  = errors.New("日志文件不可写")
+	ErrUnknownLogger   = errors.New("日志记录器不存在")
+)
+
+// 单个日志配置项的错误
+type ConfigError struct {
+	Index int    // 配置在列表中的下标，-1表示与具体配置项无关
+	Name  string // ZLogConfig.Name
+	Field string // 出错的字段
+	Value string // 出错的取值
+	Err   error  // 错误原因，可使用errors.Is与ErrXxx比较
+}
+
+func (e *ConfigError) Error() string {
+	var sb strings.Builder
+	if e.Index >= 0 {
+		fmt.Fprintf(&sb, "loggers[%d]", e.Index)
+		if e.Name != "" {
+			fmt.Fprintf(&sb, "(%s)", e.Name)
+		}
+	}
+	if e.Field != "" {
+		if sb.Len() > 0 {
+			sb.WriteString(".")
+		}
+		fmt.Fprintf(&sb, "%s=%q", e.Field, e.Value)
+	}
+	if sb.Len() > 0 {
+		sb.WriteString(": ")
+	}
+	sb.WriteString(e.Err.Error())
+	return sb.String()
+}
+
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+// 汇总后的日志配置错误
+type ConfigErrors []*ConfigError
+
+func (e ConfigErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (e ConfigErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, err := range e {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+func (e ConfigErrors) orNil() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
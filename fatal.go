@@ -0,0 +1,131 @@
+// #############################################################################
+// # File: fatal.go                                                            #
+// # Project: zlog                                                             #
+// # Created Date: 2026/10/17 20:35:55                                         #
+// # Author: realjf                                                            #
+// # -----                                                                     #
+// # Last Modified: 2026/10/17 20:56:08                                        #
+// # Modified By: realjf                                                       #
+// # -----                                                                     #
+// #                                                                           #
+// #############################################################################
+package zlog
+
+import (
+	"os"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Fatal日志写入所有日志记录器并同步、执行OnFatal回调后调用，msg为日志消息
+type FatalHook func(msg string)
+
+var (
+	fatalLock      sync.Mutex
+	fatalHook      FatalHook = FatalExit
+	fatalCallbacks []*fatalCallback
+)
+
+type fatalCallback struct {
+	f func()
+}
+
+// 退出进程，默认的Fatal处理方式
+func FatalExit(msg string) {
+	os.Exit(1)
+}
+
+// 以日志消息panic，可在测试中recover
+func FatalPanic(msg string) {
+	panic(msg)
+}
+
+// 结束当前协程，已注册的defer会被执行
+func FatalGoexit(msg string) {
+	runtime.Goexit()
+}
+
+// 设置Fatal日志的处理方式，nil表示恢复为FatalExit，返回恢复为原处理方式的函数
+func SetFatalHook(hook FatalHook) (restore func()) {
+	if hook == nil {
+		hook = FatalExit
+	}
+
+	fatalLock.Lock()
+	defer fatalLock.Unlock()
+
+	old := fatalHook
+	fatalHook = hook
+	return func() {
+		fatalLock.Lock()
+		defer fatalLock.Unlock()
+
+		fatalHook = old
+	}
+}
+
+// 注册在Fatal日志同步之后、执行FatalHook之前调用的回调，用于释放资源，返回取消注册的函数
+func OnFatal(f func()) (cancel func()) {
+	callback := &fatalCallback{f: f}
+
+	fatalLock.Lock()
+	defer fatalLock.Unlock()
+
+	fatalCallbacks = append(fatalCallbacks, callback)
+	return func() {
+		fatalLock.Lock()
+		defer fatalLock.Unlock()
+
+		for i, c := range fatalCallbacks {
+			if c == callback {
+				fatalCallbacks = append(fatalCallbacks[:i:i], fatalCallbacks[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// =========================================================== 私有方法 ===========================================================
+
+// 同步所有日志记录器，按注册顺序执行回调，最后执行FatalHook
+func (z *zLog) fatal(msg string) {
+	_ = z.Sync()
+	runFatal(msg)
+}
+
+// 按注册顺序执行回调，最后执行FatalHook
+func runFatal(msg string) {
+	fatalLock.Lock()
+	hook := fatalHook
+	callbacks := append([]*fatalCallback(nil), fatalCallbacks...)
+	fatalLock.Unlock()
+
+	for _, callback := range callbacks {
+		runFatalCallback(callback.f)
+	}
+	hook(msg)
+}
+
+// GetZCore返回的记录器写入Fatal日志后同步所有日志记录器，再执行OnFatal回调和FatalHook
+type fatalWriteHook struct {
+	root atomic.Pointer[zLogRoot] // 记录器所属的日志，重新加载后指向替换状态的日志
+}
+
+func (h *fatalWriteHook) OnWrite(ce *zapcore.CheckedEntry, _ []zapcore.Field) {
+	if root := h.root.Load(); root != nil {
+		(&zLog{zLogRoot: root}).fatal(ce.Message)
+		return
+	}
+	runFatal(ce.Message)
+}
+
+// 回调panic时继续执行后续回调和FatalHook
+func runFatalCallback(f func()) {
+	defer func() {
+		_ = recover()
+	}()
+	f()
+}
@@ -0,0 +1,147 @@
+// #############################################################################
+// # File: fatal_test.go                                                       #
+// # Project: zlog                                                             #
+// # Created Date: 2026/10/17 20:36:06                                         #
+// # Author: realjf                                                            #
+// # -----                                                                     #
+// # Last Modified: 2026/10/17 20:56:08                                        #
+// # Modified By: realjf                                                       #
+// # -----                                                                     #
+// #                                                                           #
+// #############################################################################
+package zlog_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/realjf/zlog"
+)
+
+func TestFatalHook(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "zlog.log")
+	z := zlog.NewZLog([]*zlog.ZLogConfig{
+		{
+			LogMode:  "file",
+			Encoding: "json",
+			LogFile:  logFile,
+			Async:    &zlog.AsyncConfig{},
+		},
+	})
+	defer z.Close()
+
+	restore := zlog.SetFatalHook(zlog.FatalPanic)
+	defer restore()
+
+	var order []string
+	cancel := zlog.OnFatal(func() {
+		// 回调执行时日志已同步到文件
+		if n := countLines(t, logFile, "fatal msg"); n != 1 {
+			t.Errorf("expected fatal line synced before callbacks, got %d", n)
+		}
+		order = append(order, "first")
+	})
+	defer cancel()
+	defer zlog.OnFatal(func() { panic("broken callback") })()
+	defer zlog.OnFatal(func() { order = append(order, "third") })()
+
+	expectPanic(t, true, func() { z.Fatal("fatal msg") })
+	if len(order) != 2 || order[0] != "first" || order[1] != "third" {
+		t.Fatalf("unexpected callback order %v", order)
+	}
+
+	cancel()
+	order = nil
+	expectPanic(t, true, func() { z.Fatalw("fatal msg", "k", "v") })
+	if len(order) != 1 {
+		t.Fatalf("expected cancelled callback to be skipped, got %v", order)
+	}
+
+	// GetZCore返回的记录器同样执行回调和FatalHook
+	order = nil
+	expectPanic(t, true, func() { z.GetZCore("").Fatal("raw fatal") })
+	if len(order) != 1 {
+		t.Fatalf("expected callbacks for GetZCore Fatal, got %v", order)
+	}
+}
+
+func TestFatalHookOption(t *testing.T) {
+	hooked := false
+	z := zlog.NewZLog([]*zlog.ZLogConfig{
+		{
+			LogMode: "file",
+			LogFile: filepath.Join(t.TempDir(), "zlog.log"),
+		},
+	}, zap.WithFatalHook(fatalHookFunc(func() { hooked = true })))
+	defer z.Close()
+	defer zlog.SetFatalHook(zlog.FatalPanic)()
+
+	// 通过options传入的zap.WithFatalHook不会被覆盖
+	expectPanic(t, false, func() { z.GetZCore("").Fatal("raw fatal") })
+	if !hooked {
+		t.Fatal("expected the zap fatal hook from options to be called")
+	}
+}
+
+type fatalHookFunc func()
+
+func (f fatalHookFunc) OnWrite(*zapcore.CheckedEntry, []zapcore.Field) {
+	f()
+}
+
+func TestFatalGoexit(t *testing.T) {
+	z := zlog.NewZLog([]*zlog.ZLogConfig{
+		{
+			LogMode: "file",
+			LogFile: filepath.Join(t.TempDir(), "zlog.log"),
+		},
+	})
+	defer z.Close()
+	defer zlog.SetFatalHook(zlog.FatalGoexit)()
+
+	done := make(chan bool)
+	go func() {
+		returned := false
+		defer func() { done <- returned }()
+		z.Fatalf("fatal %s", "goexit")
+		returned = true
+	}()
+	if <-done {
+		t.Fatal("expected Fatalf to end the goroutine")
+	}
+}
+
+// GetZCore返回的记录器在执行回调前同步所有日志记录器
+func TestFatalHookSyncAll(t *testing.T) {
+	dir := t.TempDir()
+	z := zlog.NewZLog([]*zlog.ZLogConfig{
+		{
+			LogMode:  "file",
+			Encoding: "json",
+			LogFile:  filepath.Join(dir, "zlog.log"),
+			Name:     "zlog",
+			Async:    &zlog.AsyncConfig{},
+		},
+		{
+			LogMode:  "file",
+			Encoding: "json",
+			LogFile:  filepath.Join(dir, "zlog2.log"),
+			Name:     "zlog2",
+			Async:    &zlog.AsyncConfig{FlushInterval: "1h"},
+		},
+	})
+	defer z.Close()
+	defer zlog.SetFatalHook(zlog.FatalPanic)()
+
+	synced := -1
+	defer zlog.OnFatal(func() { synced = countLines(t, filepath.Join(dir, "zlog2.log"), "buffered") })()
+
+	z.WithName("zlog2").Info("buffered")
+	expectPanic(t, true, func() { z.GetZCore("zlog").Fatal("raw fatal") })
+	if synced != 1 {
+		t.Fatalf("expected zlog2 to be synced before callbacks, got %d lines", synced)
+	}
+}
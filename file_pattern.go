@@ -0,0 +1,138 @@
+// #############################################################################
+// # File: file_pattern.go                                                     #
+// # Project: zlog                                                             #
+// # Created Date: 2026/10/17 20:21:10                                         #
+// # Author: realjf                                                            #
+// # -----                                                                     #
+// # Last Modified: 2026/10/17 20:21:10                                        #
+// # Modified By: realjf                                                       #
+// # -----                                                                     #
+// #                                                                           #
+// #############################################################################
+package zlog
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 日志文件名中的占位符
+//
+//	{service}   服务名，ZLogConfig.Service，默认为程序名
+//	{hostname}  主机名
+//	{pid}       进程号
+//	{name}      日志名称，ZLogConfig.Name
+//	%Y %m %d %H %M %S  年 月 日 时 分 秒，%% 表示%本身
+var filePatternToken = regexp.MustCompile(`\{(service|hostname|pid|name)\}|%[YmdHMS%]`)
+
+// 日志文件名模板，如 ./logs/{service}-{hostname}-{pid}-%Y%m%d.log
+type filePattern struct {
+	pattern string
+	values  map[string]string // {service}等占位符的取值
+}
+
+func newFilePattern(config *ZLogConfig) *filePattern {
+	if !filePatternToken.MatchString(config.LogFile) {
+		return nil
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "localhost"
+	}
+	service := config.Service
+	if service == "" {
+		service = strings.TrimSuffix(filepath.Base(os.Args[0]), filepath.Ext(os.Args[0]))
+	}
+	return &filePattern{
+		pattern: config.LogFile,
+		values: map[string]string{
+			"{service}":  service,
+			"{hostname}": hostname,
+			"{pid}":      strconv.Itoa(os.Getpid()),
+			"{name}":     config.Name,
+		},
+	}
+}
+
+// 使用t替换模板中的占位符
+func (p *filePattern) expand(t time.Time) string {
+	t = t.In(time.Local)
+	return filePatternToken.ReplaceAllStringFunc(p.pattern, func(token string) string {
+		switch token {
+		case "%Y":
+			return strconv.Itoa(t.Year())
+		case "%m":
+			return twoDigits(int(t.Month()))
+		case "%d":
+			return twoDigits(t.Day())
+		case "%H":
+			return twoDigits(t.Hour())
+		case "%M":
+			return twoDigits(t.Minute())
+		case "%S":
+			return twoDigits(t.Second())
+		case "%%":
+			return "%"
+		}
+		return p.values[token]
+	})
+}
+
+// 根据模板中最小的时间单位推断按时间切割的间隔
+func (p *filePattern) rotateInterval() time.Duration {
+	switch {
+	case strings.Contains(p.pattern, "%S"):
+		return time.Second
+	case strings.Contains(p.pattern, "%M"):
+		return time.Minute
+	case strings.Contains(p.pattern, "%H"):
+		return time.Hour
+	case strings.Contains(p.pattern, "%d"), strings.Contains(p.pattern, "%m"), strings.Contains(p.pattern, "%Y"):
+		return 24 * time.Hour
+	}
+	return 0
+}
+
+// 匹配由模板生成的日志文件及其lumberjack备份文件(name-2006-01-02T15-04-05.000.ext[.gz])
+//
+// 时间占位符和{pid}匹配任意取值，因此同一服务历次运行产生的文件都会参与清理
+func (p *filePattern) matcher() *regexp.Regexp {
+	base := filepath.Base(p.pattern)
+	ext := filepath.Ext(base)
+	if filePatternToken.MatchString(ext) {
+		ext = ""
+	}
+	name := base[:len(base)-len(ext)]
+
+	var sb strings.Builder
+	last := 0
+	for _, loc := range filePatternToken.FindAllStringIndex(name, -1) {
+		sb.WriteString(regexp.QuoteMeta(name[last:loc[0]]))
+		switch token := name[loc[0]:loc[1]]; token {
+		case "%Y":
+			sb.WriteString(`\d{4}`)
+		case "%m", "%d", "%H", "%M", "%S":
+			sb.WriteString(`\d{2}`)
+		case "%%":
+			sb.WriteString("%")
+		case "{pid}":
+			sb.WriteString(`\d+`)
+		default:
+			sb.WriteString(regexp.QuoteMeta(p.values[token]))
+		}
+		last = loc[1]
+	}
+	sb.WriteString(regexp.QuoteMeta(name[last:]))
+	return regexp.MustCompile(`^` + sb.String() + `(-\d{4}-\d{2}-\d{2}T\d{2}-\d{2}-\d{2}\.\d{3})?` + regexp.QuoteMeta(ext) + `(\.gz)?$`)
+}
+
+func twoDigits(v int) string {
+	if v < 10 {
+		return "0" + strconv.Itoa(v)
+	}
+	return strconv.Itoa(v)
+}
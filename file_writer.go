@@ -0,0 +1,289 @@
+// #############################################################################
+// # File: file_writer.go                                                      #
+// # Project: zlog                                                             #
+// # Created Date: 2026/10/17 20:20:25                                         #
+// # Author: realjf                                                            #
+// # -----                                                                     #
+// # Last Modified: 2026/10/17 20:47:20                                        #
+// # Modified By: realjf                                                       #
+// # -----                                                                     #
+// #                                                                           #
+// #############################################################################
+package zlog
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/natefinch/lumberjack.v2"
+
+	"github.com/realjf/zlog/utils/fileutil"
+)
+
+const (
+	rotateHourly = "hourly"
+	rotateDaily  = "daily"
+)
+
+// 文件日志写入器，在lumberjack按大小切割的基础上支持按时间切割和文件名模板
+type fileWriter struct {
+	lock    sync.Mutex
+	logger  *lumberjack.Logger
+	config  *ZLogConfig
+	pattern *filePattern // 文件名模板，nil表示固定文件名
+
+	interval time.Duration    // 按时间切割的间隔，0表示不按时间切割
+	offset   time.Duration    // 切割时间点相对于当天零点的偏移
+	clock    func() time.Time // 时钟
+	next     time.Time        // 下次按时间切割的时间点
+	size     int64            // 当前日志文件大小，用于判断lumberjack是否按大小切割
+
+	released bool // 已释放，之后的写入完成后立即关闭文件
+}
+
+func newFileWriter(config *ZLogConfig) (*fileWriter, error) {
+	interval, err := parseRotateInterval(config.RotateInterval)
+	if err != nil {
+		return nil, err
+	}
+	offset, err := parseRotateAt(config.RotateAt)
+	if err != nil {
+		return nil, err
+	}
+	clock := config.Clock
+	if clock == nil {
+		clock = time.Now
+	}
+	pattern := newFilePattern(config)
+	if pattern != nil && interval <= 0 {
+		interval = pattern.rotateInterval()
+	}
+
+	w := &fileWriter{
+		config:   config,
+		pattern:  pattern,
+		interval: interval,
+		offset:   offset,
+		clock:    clock,
+	}
+	now := clock()
+	filename := w.filename(now)
+	dir := filepath.Dir(filename)
+	if err := fileutil.MkdirIfNecessary(dir); err != nil {
+		return nil, errors.Wrapf(err, "创建日志目录[%s]失败", dir)
+	}
+	w.logger = w.newLumberjack(filename)
+	if interval > 0 {
+		// 已有日志文件时从其最后修改时间开始计算，保证重启后跨周期的日志不会写入同一个文件
+		last := now
+		if info, err := os.Stat(filename); err == nil && info.ModTime().Before(last) {
+			last = info.ModTime()
+		}
+		w.next = w.nextRotateTime(last)
+	}
+	w.cleanup(now)
+	return w, nil
+}
+
+// =========================================================== 接口方法 ===========================================================
+
+func (w *fileWriter) Write(p []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.interval > 0 {
+		if now := w.clock(); !now.Before(w.next) {
+			if err := w.rotate(now); err != nil {
+				return 0, err
+			}
+			w.next = w.nextRotateTime(now)
+		}
+	}
+	if w.released {
+		// 重新加载或关闭后仍在使用旧写入器的日志，写入后关闭文件避免泄漏文件句柄
+		n, err := w.write(p)
+		_ = w.logger.Close()
+		return n, err
+	}
+	return w.write(p)
+}
+
+func (w *fileWriter) Sync() error {
+	return nil
+}
+
+// 关闭当前日志文件，下次写入时会按原路径重新打开
+func (w *fileWriter) Close() error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	return w.logger.Close()
+}
+
+// 释放写入器，重新加载或关闭日志时调用
+func (w *fileWriter) release() error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.released = true
+	return w.logger.Close()
+}
+
+// =========================================================== 私有方法 ===========================================================
+
+// 按时间切割：文件名模板展开后发生变化时切换到新文件，否则由lumberjack重命名当前文件
+func (w *fileWriter) rotate(now time.Time) error {
+	filename := w.filename(now)
+	if filename == w.logger.Filename {
+		if err := w.logger.Rotate(); err != nil {
+			return err
+		}
+		w.size = 0
+		w.cleanup(now)
+		return nil
+	}
+	if err := w.logger.Close(); err != nil {
+		return err
+	}
+	w.logger = w.newLumberjack(filename)
+	w.cleanup(now)
+	return nil
+}
+
+// 写入日志，lumberjack按大小切割出新的备份文件后按模板清理历史日志文件
+func (w *fileWriter) write(p []byte) (int, error) {
+	// 与lumberjack的判断保持一致：写入后超过MaxSize时先切割再写入
+	rotating := w.size+int64(len(p)) > w.maxSize()
+	n, err := w.logger.Write(p)
+	if rotating && err == nil {
+		w.size = 0
+		w.cleanup(w.clock())
+	}
+	w.size += int64(n)
+	return n, err
+}
+
+// lumberjack切割文件的大小上限，MaxSize为0时lumberjack默认为100M
+func (w *fileWriter) maxSize() int64 {
+	if w.config.MaxSize <= 0 {
+		return 100 * 1024 * 1024
+	}
+	return int64(w.config.MaxSize) * 1024 * 1024
+}
+
+func (w *fileWriter) filename(now time.Time) string {
+	if w.pattern == nil {
+		return w.config.LogFile
+	}
+	return w.pattern.expand(now)
+}
+
+func (w *fileWriter) newLumberjack(filename string) *lumberjack.Logger {
+	logger := &lumberjack.Logger{
+		Filename:   filename,
+		MaxSize:    w.config.MaxSize,
+		MaxAge:     w.config.MaxAge,
+		MaxBackups: w.config.MaxBackups,
+		Compress:   w.config.Compress,
+		LocalTime:  true,
+	}
+	if w.pattern != nil {
+		// 使用文件名模板时由cleanup统一按模板清理，lumberjack只负责按大小切割和压缩
+		logger.MaxAge = 0
+		logger.MaxBackups = 0
+	}
+	w.size = 0
+	if info, err := os.Stat(filename); err == nil {
+		w.size = info.Size()
+	}
+	return logger
+}
+
+// 清理由文件名模板生成的历史日志文件，保留最新的MaxBackups个且不超过MaxAge天
+func (w *fileWriter) cleanup(now time.Time) {
+	if w.pattern == nil || (w.config.MaxBackups <= 0 && w.config.MaxAge <= 0) {
+		return
+	}
+	dir := filepath.Dir(w.logger.Filename)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	matcher := w.pattern.matcher()
+	current := filepath.Base(w.logger.Filename)
+	files := make([]os.FileInfo, 0)
+	for _, entry := range entries {
+		if entry.IsDir() || entry.Name() == current || !matcher.MatchString(entry.Name()) {
+			continue
+		}
+		if info, err := entry.Info(); err == nil {
+			files = append(files, info)
+		}
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].ModTime().After(files[j].ModTime())
+	})
+
+	cutoff := now.Add(-time.Duration(w.config.MaxAge) * 24 * time.Hour)
+	for i, file := range files {
+		if (w.config.MaxBackups > 0 && i >= w.config.MaxBackups) || (w.config.MaxAge > 0 && file.ModTime().Before(cutoff)) {
+			_ = os.Remove(filepath.Join(dir, file.Name()))
+		}
+	}
+}
+
+// 计算t之后的第一个切割时间点：当天零点 + offset + k * interval
+func (w *fileWriter) nextRotateTime(t time.Time) time.Time {
+	t = t.In(time.Local)
+	y, m, d := t.Date()
+	next := time.Date(y, m, d, 0, 0, 0, 0, time.Local).Add(w.offset)
+	for next.After(t) {
+		next = w.step(next, -1)
+	}
+	for !next.After(t) {
+		next = w.step(next, 1)
+	}
+	return next
+}
+
+// 按天整数倍的间隔使用日历日期计算，避免夏令时导致切割时间点偏移
+func (w *fileWriter) step(t time.Time, n int) time.Time {
+	if w.interval%(24*time.Hour) == 0 {
+		return t.AddDate(0, 0, n*int(w.interval/(24*time.Hour)))
+	}
+	return t.Add(time.Duration(n) * w.interval)
+}
+
+// 解析按时间切割的间隔：hourly|daily|time.Duration格式(如"30m"、"6h")
+func parseRotateInterval(interval string) (time.Duration, error) {
+	switch interval {
+	case "":
+		return 0, nil
+	case rotateHourly:
+		return time.Hour, nil
+	case rotateDaily:
+		return 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(interval)
+	if err != nil || d < time.Second {
+		return 0, errors.WithMessagef(ErrInvalidValue, "rotate_interval取值非法：%s", interval)
+	}
+	return d, nil
+}
+
+// 解析切割时间点：HH:MM
+func parseRotateAt(at string) (time.Duration, error) {
+	if at == "" {
+		return 0, nil
+	}
+	t, err := time.Parse("15:04", at)
+	if err != nil {
+		return 0, errors.WithMessagef(ErrInvalidValue, "rotate_at取值非法：%s", at)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
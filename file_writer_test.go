@@ -0,0 +1,170 @@
+// #############################################################################
+// # File: file_writer_test.go                                                 #
+// # Project: zlog                                                             #
+// # Created Date: 2026/10/17 20:20:34                                         #
+// # Author: realjf                                                            #
+// # -----                                                                     #
+// # Last Modified: 2026/10/17 20:47:20                                        #
+// # Modified By: realjf                                                       #
+// # -----                                                                     #
+// #                                                                           #
+// #############################################################################
+package zlog_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/realjf/zlog"
+)
+
+type fakeClock struct {
+	lock sync.Mutex
+	now  time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Set(now time.Time) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.now = now
+}
+
+func TestRotateDaily(t *testing.T) {
+	dir := t.TempDir()
+	logFile := filepath.Join(dir, "zlog.log")
+	clock := &fakeClock{now: time.Date(2024, 11, 11, 10, 0, 0, 0, time.Local)}
+	z := zlog.NewZLog([]*zlog.ZLogConfig{
+		{
+			LogMode:        "file",
+			Encoding:       "json",
+			LogFile:        logFile,
+			RotateInterval: "daily",
+			RotateAt:       "02:00",
+			Clock:          clock.Now,
+		},
+	})
+
+	z.Info("day1 morning")
+	clock.Set(time.Date(2024, 11, 12, 1, 59, 0, 0, time.Local))
+	z.Info("day1 night")
+	clock.Set(time.Date(2024, 11, 12, 2, 0, 0, 0, time.Local))
+	z.Info("day2")
+
+	data, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content := string(data); !strings.Contains(content, "day2") || strings.Contains(content, "day1") {
+		t.Fatalf("unexpected current file: %s", content)
+	}
+
+	backups, err := filepath.Glob(filepath.Join(dir, "zlog-*.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 1 {
+		t.Fatalf("expected 1 backup, got %v", backups)
+	}
+	data, err = os.ReadFile(backups[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content := string(data); !strings.Contains(content, "day1 morning") || !strings.Contains(content, "day1 night") {
+		t.Fatalf("unexpected backup file: %s", content)
+	}
+}
+
+func TestRotateIntervalInvalid(t *testing.T) {
+	_, err := zlog.NewZLogE([]*zlog.ZLogConfig{
+		{
+			LogMode:        "file",
+			LogFile:        filepath.Join(t.TempDir(), "zlog.log"),
+			RotateInterval: "weekly",
+			RotateAt:       "25:00",
+		},
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	t.Log(err)
+}
+
+func TestFilePattern(t *testing.T) {
+	dir := t.TempDir()
+	clock := &fakeClock{now: time.Date(2024, 11, 1, 10, 0, 0, 0, time.Local)}
+	z := zlog.NewZLog([]*zlog.ZLogConfig{
+		{
+			LogMode:    "file",
+			Encoding:   "json",
+			LogFile:    filepath.Join(dir, "{service}-{name}-{pid}-%Y%m%d.log"),
+			Service:    "demo",
+			Name:       "zlog",
+			MaxBackups: 2,
+			Clock:      clock.Now,
+		},
+	})
+
+	var files []string
+	for day := 1; day <= 4; day++ {
+		now := time.Date(2024, 11, day, 10, 0, 0, 0, time.Local)
+		clock.Set(now)
+		z.Infof("day%d", day)
+
+		file := filepath.Join(dir, fmt.Sprintf("demo-zlog-%d-202411%02d.log", os.Getpid(), day))
+		if err := os.Chtimes(file, now, now); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, file)
+	}
+
+	if _, err := os.Stat(files[0]); !os.IsNotExist(err) {
+		t.Errorf("expected %s to be removed, got %v", files[0], err)
+	}
+	for day, file := range files[1:] {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), fmt.Sprintf("day%d", day+2)) {
+			t.Errorf("unexpected content in %s: %s", file, data)
+		}
+	}
+}
+
+func TestFilePatternSizeRotate(t *testing.T) {
+	dir := t.TempDir()
+	z := zlog.NewZLog([]*zlog.ZLogConfig{
+		{
+			LogMode:    "file",
+			Encoding:   "json",
+			LogFile:    filepath.Join(dir, "app-{pid}.log"),
+			MaxSize:    1,
+			MaxBackups: 2,
+		},
+	})
+
+	msg := strings.Repeat("x", 64*1024)
+	for i := 0; i < 6*16; i++ {
+		z.Info(msg)
+	}
+	z.Sync()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 {
+		t.Errorf("expected 3 files, got %d", len(entries))
+	}
+}
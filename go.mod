@@ -6,11 +6,10 @@ toolchain go1.22.9
 
 require (
 	github.com/pkg/errors v0.9.1
+	go.uber.org/multierr v1.10.0
 	go.uber.org/zap v1.27.0
 	gopkg.in/natefinch/lumberjack.v2 v2.2.1
+	gopkg.in/yaml.v3 v3.0.1
 )
 
-require (
-	github.com/stretchr/testify v1.9.0 // indirect
-	go.uber.org/multierr v1.10.0 // indirect
-)
+require github.com/stretchr/testify v1.9.0 // indirect
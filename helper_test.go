@@ -0,0 +1,46 @@
+// #############################################################################
+// # File: helper_test.go                                                      #
+// # Project: zlog                                                             #
+// # Created Date: 2026/10/17 20:57:21                                         #
+// # Author: realjf                                                            #
+// # -----                                                                     #
+// # Last Modified: 2026/10/17 20:57:21                                        #
+// # Modified By: realjf                                                       #
+// # -----                                                                     #
+// #                                                                           #
+// #############################################################################
+package zlog_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+// json日志中的一条日志
+type logEntry struct {
+	Msg        string `json:"msg"`
+	Caller     string `json:"caller"`
+	Stacktrace string `json:"stacktrace"`
+}
+
+// 按顺序读取json日志文件中的每条日志
+func readEntries(t *testing.T, path string) []logEntry {
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	entries := make([]logEntry, 0)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry logEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
@@ -0,0 +1,146 @@
+// #############################################################################
+// # File: level_handler.go                                                    #
+// # Project: zlog                                                             #
+// # Created Date: 2026/10/17 20:15:16                                         #
+// # Author: realjf                                                            #
+// # -----                                                                     #
+// # Last Modified: 2026/10/17 20:34:12                                        #
+// # Modified By: realjf                                                       #
+// # -----                                                                     #
+// #                                                                           #
+// #############################################################################
+package zlog
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// 日志记录器的级别信息
+type LoggerLevel struct {
+	Name     string   `json:"name"`
+	Level    LogLevel `json:"level"`
+	LogMode  string   `json:"log_mode"`
+	Encoding string   `json:"encoding"`
+	Default  bool     `json:"default"`
+}
+
+type levelLister interface {
+	loggerLevels() []LoggerLevel
+}
+
+type levelHandler struct {
+	logger func() IZLog
+}
+
+type levelRequest struct {
+	Name  string   `json:"name"`
+	Level LogLevel `json:"level"`
+}
+
+type levelErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// =========================================================== 构造方法 ===========================================================
+
+// 查看和修改全局日志级别的http.Handler
+//
+//	GET  /            列出所有日志记录器
+//	GET  /?name=zlog  查看指定日志记录器
+//	PUT  /            修改日志级别，请求体：{"name": "zlog", "level": "debug"}，name也可以通过查询参数指定
+func LevelHandler() http.Handler {
+	return &levelHandler{logger: ZLog}
+}
+
+// 查看和修改指定日志级别的http.Handler
+func NewLevelHandler(logger IZLog) http.Handler {
+	return &levelHandler{logger: func() IZLog { return logger }}
+}
+
+// =========================================================== 接口方法 ===========================================================
+
+func (h *levelHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := h.logger()
+	lister, ok := logger.(levelLister)
+	if !ok {
+		writeLevelError(w, http.StatusNotImplemented, errors.New("日志不支持级别管理"))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		levels := lister.loggerLevels()
+		name := r.URL.Query().Get("name")
+		if name == "" {
+			writeLevelJson(w, http.StatusOK, levels)
+			return
+		}
+		for _, level := range levels {
+			if level.Name == name {
+				writeLevelJson(w, http.StatusOK, level)
+				return
+			}
+		}
+		writeLevelError(w, http.StatusNotFound, errors.WithMessagef(ErrUnknownLogger, "%s", name))
+	case http.MethodPut:
+		req := levelRequest{Name: r.URL.Query().Get("name")}
+		decoder := json.NewDecoder(r.Body)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&req); err != nil {
+			writeLevelError(w, http.StatusBadRequest, errors.WithMessage(err, "请求体格式错误"))
+			return
+		}
+		if err := logger.SetLevel(req.Name, req.Level); err != nil {
+			status := http.StatusBadRequest
+			if errors.Is(err, ErrUnknownLogger) {
+				status = http.StatusNotFound
+			}
+			writeLevelError(w, status, err)
+			return
+		}
+		for _, level := range lister.loggerLevels() {
+			if level.Name == req.Name {
+				writeLevelJson(w, http.StatusOK, level)
+				return
+			}
+		}
+		writeLevelError(w, http.StatusNotFound, errors.WithMessagef(ErrUnknownLogger, "%s", req.Name))
+	default:
+		w.Header().Set("Allow", http.MethodGet+", "+http.MethodPut)
+		writeLevelError(w, http.StatusMethodNotAllowed, errors.Errorf("不支持的请求方法：%s", r.Method))
+	}
+}
+
+// =========================================================== 私有方法 ===========================================================
+
+func (z *zLog) loggerLevels() []LoggerLevel {
+	state := z.state.Load()
+	levels := make([]LoggerLevel, 0, len(state.cfgs))
+	for name, cfg := range state.cfgs {
+		levels = append(levels, LoggerLevel{
+			Name:     name,
+			Level:    FromZapLevel(state.levels[name].Level()),
+			LogMode:  cfg.LogMode,
+			Encoding: cfg.Encoding,
+			Default:  cfg.Default,
+		})
+	}
+	sort.Slice(levels, func(i, j int) bool {
+		return levels[i].Name < levels[j].Name
+	})
+	return levels
+}
+
+func writeLevelJson(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeLevelError(w http.ResponseWriter, status int, err error) {
+	writeLevelJson(w, status, levelErrorResponse{Error: err.Error()})
+}
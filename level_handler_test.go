@@ -0,0 +1,98 @@
+// #############################################################################
+// # File: level_handler_test.go                                               #
+// # Project: zlog                                                             #
+// # Created Date: 2026/10/17 20:15:25                                         #
+// # Author: realjf                                                            #
+// # -----                                                                     #
+// # Last Modified: 2026/10/17 20:15:25                                        #
+// # Modified By: realjf                                                       #
+// # -----                                                                     #
+// #                                                                           #
+// #############################################################################
+package zlog_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/realjf/zlog"
+)
+
+func TestLevelHandler(t *testing.T) {
+	z := zlog.NewZLog([]*zlog.ZLogConfig{
+		{
+			Level:    "info",
+			Encoding: "json",
+			Name:     "zlog",
+			Default:  true,
+		},
+		{
+			Level:    "error",
+			LogMode:  "console",
+			Encoding: "console",
+			Name:     "zlog2",
+		},
+	})
+	server := httptest.NewServer(zlog.NewLevelHandler(z))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var levels []zlog.LoggerLevel
+	if err := json.NewDecoder(resp.Body).Decode(&levels); err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if len(levels) != 2 || levels[0].Name != "zlog" || levels[0].Level != "info" || !levels[0].Default ||
+		levels[1].Name != "zlog2" || levels[1].Level != "error" || levels[1].Encoding != "console" {
+		t.Fatalf("unexpected levels %+v", levels)
+	}
+
+	req, _ := http.NewRequest(http.MethodPut, server.URL, strings.NewReader(`{"name": "zlog2", "level": "debug"}`))
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var level zlog.LoggerLevel
+	if err := json.NewDecoder(resp.Body).Decode(&level); err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || level.Level != "debug" {
+		t.Fatalf("unexpected response %d %+v", resp.StatusCode, level)
+	}
+	if current, _ := z.GetLevel("zlog2"); current != "debug" {
+		t.Fatalf("level not changed: %s", current)
+	}
+
+	cases := []struct {
+		method string
+		target string
+		body   string
+		status int
+	}{
+		{http.MethodGet, "/?name=zlog2", "", http.StatusOK},
+		{http.MethodGet, "/?name=zlog3", "", http.StatusNotFound},
+		{http.MethodPut, "/?name=zlog", `{"level": "warn"}`, http.StatusOK},
+		{http.MethodPut, "/", `{"name": "zlog3", "level": "warn"}`, http.StatusNotFound},
+		{http.MethodPut, "/", `{"name": "zlog", "level": "verbose"}`, http.StatusBadRequest},
+		{http.MethodPut, "/", `{"name": "zlog", "lvl": "warn"}`, http.StatusBadRequest},
+		{http.MethodPost, "/", "", http.StatusMethodNotAllowed},
+	}
+	handler := zlog.NewLevelHandler(z)
+	for _, c := range cases {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(c.method, c.target, strings.NewReader(c.body)))
+		if rec.Code != c.status {
+			t.Errorf("%s %s %s: expected %d, got %d: %s", c.method, c.target, c.body, c.status, rec.Code, rec.Body.String())
+		}
+	}
+	if current, _ := z.GetLevel("zlog"); current != "warn" {
+		t.Fatalf("level not changed: %s", current)
+	}
+}
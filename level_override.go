@@ -0,0 +1,109 @@
+// #############################################################################
+// # File: level_override.go                                                   #
+// # Project: zlog                                                             #
+// # Created Date: 2026/10/17 20:15:45                                         #
+// # Author: realjf                                                            #
+// # -----                                                                     #
+// # Last Modified: 2026/10/17 20:34:12                                        #
+// # Modified By: realjf                                                       #
+// # -----                                                                     #
+// #                                                                           #
+// #############################################################################
+package zlog
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// 临时修改的日志级别，到期后恢复为配置的级别
+type levelOverride struct {
+	level LogLevel
+	timer *time.Timer
+}
+
+// 临时修改指定日志记录器的级别，duration后自动恢复为ZLogConfig.Level
+//
+// 新的临时修改或SetLevel会取消尚未到期的临时修改
+func (z *zLog) SetLevelFor(name string, level LogLevel, duration time.Duration) error {
+	if !level.valid() {
+		return &ConfigError{Index: -1, Name: name, Field: "level", Value: level.String(), Err: ErrInvalidLevel}
+	}
+	if duration <= 0 {
+		return &ConfigError{Index: -1, Name: name, Field: "duration", Value: duration.String(), Err: ErrInvalidValue}
+	}
+
+	z.lock.Lock()
+	defer z.lock.Unlock()
+
+	atomicLevel, ok := z.state.Load().levels[name]
+	if !ok {
+		return errors.WithMessagef(ErrUnknownLogger, "%s", name)
+	}
+	z.cancelLevelOverride(name)
+
+	from := FromZapLevel(atomicLevel.Level())
+	z.logLevelChange(name, atomicLevel, level, "临时修改日志级别",
+		zap.String("from", from.String()),
+		zap.String("to", level.String()),
+		zap.Duration("duration", duration),
+	)
+
+	override := &levelOverride{level: level}
+	override.timer = time.AfterFunc(duration, func() {
+		z.revertLevelOverride(name, override)
+	})
+	if z.overrides == nil {
+		z.overrides = make(map[string]*levelOverride)
+	}
+	z.overrides[name] = override
+	return nil
+}
+
+// 取消尚未到期的临时修改，调用方需持有z.lock
+func (z *zLog) cancelLevelOverride(name string) {
+	if override, ok := z.overrides[name]; ok {
+		override.timer.Stop()
+		delete(z.overrides, name)
+	}
+}
+
+func (z *zLog) revertLevelOverride(name string, override *levelOverride) {
+	z.lock.Lock()
+	defer z.lock.Unlock()
+
+	// 已被新的临时修改或SetLevel取消
+	if z.overrides[name] != override {
+		return
+	}
+	delete(z.overrides, name)
+
+	state := z.state.Load()
+	atomicLevel, ok := state.levels[name]
+	if !ok {
+		return
+	}
+	level := state.cfgs[name].Level
+	if level == "" {
+		level = LogLevelDebug
+	}
+	z.logLevelChange(name, atomicLevel, level, "临时日志级别到期，恢复为配置的级别",
+		zap.String("from", override.level.String()),
+		zap.String("to", level.String()),
+	)
+}
+
+// 修改日志级别并记录一条warn日志，日志在修改前后两个级别中较详细的一个下输出，调用方需持有z.lock
+func (z *zLog) logLevelChange(name string, atomicLevel zap.AtomicLevel, level LogLevel, msg string, fields ...zapcore.Field) {
+	logger := z.state.Load().loggers[name].With(zap.String("logger", name))
+	if level.ZapLevel() < atomicLevel.Level() {
+		atomicLevel.SetLevel(level.ZapLevel())
+		logger.Warn(msg, fields...)
+		return
+	}
+	logger.Warn(msg, fields...)
+	atomicLevel.SetLevel(level.ZapLevel())
+}
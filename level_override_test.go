@@ -0,0 +1,99 @@
+// #############################################################################
+// # File: level_override_test.go                                              #
+// # Project: zlog                                                             #
+// # Created Date: 2026/10/17 20:15:55                                         #
+// # Author: realjf                                                            #
+// # -----                                                                     #
+// # Last Modified: 2026/10/17 20:15:55                                        #
+// # Modified By: realjf                                                       #
+// # -----                                                                     #
+// #                                                                           #
+// #############################################################################
+package zlog_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/realjf/zlog"
+)
+
+func TestSetLevelFor(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "zlog.log")
+	z := zlog.NewZLog([]*zlog.ZLogConfig{
+		{
+			Level:    "info",
+			LogMode:  "file",
+			Encoding: "json",
+			LogFile:  logFile,
+			Name:     "zlog",
+		},
+	})
+
+	if err := z.SetLevelFor("zlog", "debug", 50*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if level, _ := z.GetLevel("zlog"); level != "debug" {
+		t.Fatalf("expected debug, got %s", level)
+	}
+	z.Debug("debug during override")
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if level, _ := z.GetLevel("zlog"); level == "info" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("level was not reverted")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	z.Debug("debug after override")
+
+	data, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(data)
+	for _, msg := range []string{"临时修改日志级别", "debug during override", "恢复为配置的级别"} {
+		if !strings.Contains(content, msg) {
+			t.Errorf("missing %q in %s", msg, content)
+		}
+	}
+	if strings.Contains(content, "debug after override") {
+		t.Errorf("unexpected debug line in %s", content)
+	}
+}
+
+func TestSetLevelForCancel(t *testing.T) {
+	z := zlog.NewZLog([]*zlog.ZLogConfig{
+		{
+			Level: "info",
+			Name:  "zlog",
+		},
+	})
+
+	if err := z.SetLevelFor("zlog", "debug", 20*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if err := z.SetLevelFor("zlog", "error", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if level, _ := z.GetLevel("zlog"); level != "error" {
+		t.Fatalf("expected error, got %s", level)
+	}
+
+	if err := z.SetLevel("zlog", "warn"); err != nil {
+		t.Fatal(err)
+	}
+	if level, _ := z.GetLevel("zlog"); level != "warn" {
+		t.Fatalf("expected warn, got %s", level)
+	}
+	if err := z.SetLevelFor("zlog", "debug", 0); err == nil {
+		t.Fatal("expected error for non-positive duration")
+	}
+}
@@ -4,7 +4,7 @@
 // # Created Date: 2024/10/08 15:32:56                                         #
 // # Author: realjf                                                            #
 // # -----                                                                     #
-// # Last Modified: 2024/10/09 13:49:48                                        #
+// # Last Modified: 2026/10/17 20:35:33                                        #
 // # Modified By: realjf                                                       #
 // # -----                                                                     #
 // #                                                                           #
@@ -12,6 +12,10 @@
 package zlog
 
 import (
+	"log/slog"
+	"strings"
+
+	"github.com/pkg/errors"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -19,29 +23,158 @@ import (
 type LogLevel string
 
 const (
-	logLevelDebug LogLevel = "debug"
-	logLevelInfo  LogLevel = "info"
-	logLevelWarn  LogLevel = "warn"
-	logLevelError LogLevel = "error"
-	logLevelFatal LogLevel = "fatal"
+	LogLevelDebug  LogLevel = "debug"
+	LogLevelInfo   LogLevel = "info"
+	LogLevelWarn   LogLevel = "warn"
+	LogLevelError  LogLevel = "error"
+	LogLevelDPanic LogLevel = "dpanic" // 开发模式下写入后panic
+	LogLevelPanic  LogLevel = "panic"  // 写入后panic
+	LogLevelFatal  LogLevel = "fatal"
+)
+
+// 日志级别的别名
+var logLevelAliases = map[string]LogLevel{
+	"debug":   LogLevelDebug,
+	"info":    LogLevelInfo,
+	"warn":    LogLevelWarn,
+	"warning": LogLevelWarn,
+	"error":   LogLevelError,
+	"err":     LogLevelError,
+	"dpanic":  LogLevelDPanic,
+	"panic":   LogLevelPanic,
+	"fatal":   LogLevelFatal,
+}
+
+// 解析日志级别，不区分大小写，支持warning、err等别名
+func ParseLogLevel(text string) (LogLevel, error) {
+	if level, ok := logLevelAliases[strings.ToLower(strings.TrimSpace(text))]; ok {
+		return level, nil
+	}
+	return "", errors.WithMessagef(ErrInvalidLevel, "%s", text)
+}
+
+// 从zap日志级别转换
+func FromZapLevel(level zapcore.Level) LogLevel {
+	switch {
+	case level <= zap.DebugLevel:
+		return LogLevelDebug
+	case level == zap.InfoLevel:
+		return LogLevelInfo
+	case level == zap.WarnLevel:
+		return LogLevelWarn
+	case level == zap.ErrorLevel:
+		return LogLevelError
+	case level == zap.DPanicLevel:
+		return LogLevelDPanic
+	case level == zap.PanicLevel:
+		return LogLevelPanic
+	default:
+		return LogLevelFatal
+	}
+}
+
+// 从slog日志级别转换，介于两个级别之间时取较低的级别
+func FromSlogLevel(level slog.Level) LogLevel {
+	switch {
+	case level < slog.LevelInfo:
+		return LogLevelDebug
+	case level < slog.LevelWarn:
+		return LogLevelInfo
+	case level < slog.LevelError:
+		return LogLevelWarn
+	case level < slogLevelDPanic:
+		return LogLevelError
+	case level < slogLevelPanic:
+		return LogLevelDPanic
+	case level < slogLevelFatal:
+		return LogLevelPanic
+	default:
+		return LogLevelFatal
+	}
+}
+
+// slog没有dpanic、panic和fatal级别，使用error与error+4之间的级别表示
+const (
+	slogLevelDPanic = slog.LevelError + 2
+	slogLevelPanic  = slog.LevelError + 3
+	slogLevelFatal  = slog.LevelError + 4
 )
 
+// =========================================================== 接口方法 ===========================================================
+
 func (l LogLevel) String() string {
 	return string(l)
 }
 
-func (l LogLevel) toZapLevel() (level zapcore.Level) {
-	switch l {
-	case logLevelInfo:
-		level = zap.InfoLevel
-	case logLevelWarn:
-		level = zap.WarnLevel
-	case logLevelError:
-		level = zap.ErrorLevel
-	case logLevelFatal:
-		level = zap.FatalLevel
+func (l LogLevel) MarshalText() ([]byte, error) {
+	return []byte(l), nil
+}
+
+// 解析yaml/json中的日志级别，空字符串表示使用默认级别
+func (l *LogLevel) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*l = ""
+		return nil
+	}
+	level, err := ParseLogLevel(string(text))
+	if err != nil {
+		return err
+	}
+	*l = level
+	return nil
+}
+
+// 转换为zap日志级别，空字符串或非法取值返回debug
+func (l LogLevel) ZapLevel() zapcore.Level {
+	switch l.canonical() {
+	case LogLevelInfo:
+		return zap.InfoLevel
+	case LogLevelWarn:
+		return zap.WarnLevel
+	case LogLevelError:
+		return zap.ErrorLevel
+	case LogLevelDPanic:
+		return zap.DPanicLevel
+	case LogLevelPanic:
+		return zap.PanicLevel
+	case LogLevelFatal:
+		return zap.FatalLevel
 	default:
-		level = zap.DebugLevel
+		return zap.DebugLevel
+	}
+}
+
+// 转换为slog日志级别，空字符串或非法取值返回debug
+func (l LogLevel) SlogLevel() slog.Level {
+	switch l.canonical() {
+	case LogLevelInfo:
+		return slog.LevelInfo
+	case LogLevelWarn:
+		return slog.LevelWarn
+	case LogLevelError:
+		return slog.LevelError
+	case LogLevelDPanic:
+		return slogLevelDPanic
+	case LogLevelPanic:
+		return slogLevelPanic
+	case LogLevelFatal:
+		return slogLevelFatal
+	default:
+		return slog.LevelDebug
+	}
+}
+
+// =========================================================== 私有方法 ===========================================================
+
+func (l LogLevel) valid() bool {
+	_, err := ParseLogLevel(string(l))
+	return err == nil
+}
+
+// 规范化的日志级别，如WARNING转换为warn
+func (l LogLevel) canonical() LogLevel {
+	if level, err := ParseLogLevel(string(l)); err == nil {
+		return level
 	}
-	return level
+	return l
 }
@@ -0,0 +1,112 @@
+// #############################################################################
+// # File: log_level_test.go                                                   #
+// # Project: zlog                                                             #
+// # Created Date: 2026/10/17 20:34:00                                         #
+// # Author: realjf                                                            #
+// # -----                                                                     #
+// # Last Modified: 2026/10/17 20:35:33                                        #
+// # Modified By: realjf                                                       #
+// # -----                                                                     #
+// #                                                                           #
+// #############################################################################
+package zlog_test
+
+import (
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/yaml.v3"
+
+	"github.com/realjf/zlog"
+)
+
+func TestParseLogLevel(t *testing.T) {
+	cases := map[string]zlog.LogLevel{
+		"debug":   zlog.LogLevelDebug,
+		"INFO":    zlog.LogLevelInfo,
+		" Warn ":  zlog.LogLevelWarn,
+		"warning": zlog.LogLevelWarn,
+		"err":     zlog.LogLevelError,
+		"Fatal":   zlog.LogLevelFatal,
+	}
+	for text, want := range cases {
+		if level, err := zlog.ParseLogLevel(text); err != nil || level != want {
+			t.Errorf("ParseLogLevel(%q) = %q, %v, want %q", text, level, err, want)
+		}
+	}
+	for _, text := range []string{"", "verbose", "debugg"} {
+		if _, err := zlog.ParseLogLevel(text); !errors.Is(err, zlog.ErrInvalidLevel) {
+			t.Errorf("ParseLogLevel(%q): expected ErrInvalidLevel, got %v", text, err)
+		}
+	}
+}
+
+func TestLogLevelMarshal(t *testing.T) {
+	var config zlog.ZLogConfig
+	if err := json.Unmarshal([]byte(`{"level": "WARNING"}`), &config); err != nil || config.Level != zlog.LogLevelWarn {
+		t.Fatalf("json: got %q, %v", config.Level, err)
+	}
+	if err := yaml.Unmarshal([]byte("level: Err\n"), &config); err != nil || config.Level != zlog.LogLevelError {
+		t.Fatalf("yaml: got %q, %v", config.Level, err)
+	}
+	if err := json.Unmarshal([]byte(`{"level": "verbose"}`), &config); err == nil {
+		t.Fatal("json: expected error")
+	}
+	if err := yaml.Unmarshal([]byte("level: verbose\n"), &config); err == nil {
+		t.Fatal("yaml: expected error")
+	}
+
+	data, err := json.Marshal(struct {
+		Level zlog.LogLevel `json:"level"`
+	}{zlog.LogLevelInfo})
+	if err != nil || string(data) != `{"level":"info"}` {
+		t.Fatalf("marshal: got %s, %v", data, err)
+	}
+}
+
+func TestLogLevelConversion(t *testing.T) {
+	cases := []struct {
+		level zlog.LogLevel
+		zap   zapcore.Level
+		slog  slog.Level
+	}{
+		{zlog.LogLevelDebug, zapcore.DebugLevel, slog.LevelDebug},
+		{zlog.LogLevelInfo, zapcore.InfoLevel, slog.LevelInfo},
+		{zlog.LogLevelWarn, zapcore.WarnLevel, slog.LevelWarn},
+		{zlog.LogLevelError, zapcore.ErrorLevel, slog.LevelError},
+		{zlog.LogLevelDPanic, zapcore.DPanicLevel, slog.LevelError + 2},
+		{zlog.LogLevelPanic, zapcore.PanicLevel, slog.LevelError + 3},
+		{zlog.LogLevelFatal, zapcore.FatalLevel, slog.LevelError + 4},
+	}
+	for _, c := range cases {
+		if got := c.level.ZapLevel(); got != c.zap {
+			t.Errorf("%s.ZapLevel() = %v, want %v", c.level, got, c.zap)
+		}
+		if got := c.level.SlogLevel(); got != c.slog {
+			t.Errorf("%s.SlogLevel() = %v, want %v", c.level, got, c.slog)
+		}
+		if got := zlog.FromZapLevel(c.zap); got != c.level {
+			t.Errorf("FromZapLevel(%v) = %s, want %s", c.zap, got, c.level)
+		}
+		if got := zlog.FromSlogLevel(c.slog); got != c.level {
+			t.Errorf("FromSlogLevel(%v) = %s, want %s", c.slog, got, c.level)
+		}
+	}
+	if got := zlog.FromSlogLevel(slog.LevelInfo + 2); got != zlog.LogLevelInfo {
+		t.Errorf("FromSlogLevel(INFO+2) = %s, want info", got)
+	}
+
+}
+
+func TestNewZLogInvalidLevel(t *testing.T) {
+	// 非法的日志级别不再被当作debug
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic")
+		}
+	}()
+	zlog.NewZLog([]*zlog.ZLogConfig{{Level: "verbose", LogMode: "console"}})
+}
@@ -0,0 +1,206 @@
+// #############################################################################
+// # File: reload.go                                                           #
+// # Project: zlog                                                             #
+// # Created Date: 2026/10/17 20:12:30                                         #
+// # Author: realjf                                                            #
+// # -----                                                                     #
+// # Last Modified: 2026/10/17 20:24:20                                        #
+// # Modified By: realjf                                                       #
+// # -----                                                                     #
+// #                                                                           #
+// #############################################################################
+package zlog
+
+import (
+	"crypto/sha256"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const defaultReloadInterval = 5 * time.Second
+
+// 日志配置文件监听器，配置文件内容变化时重新加载日志配置
+type ZLogWatcher struct {
+	z        *zLog
+	path     string
+	interval time.Duration
+	options  []zap.Option
+
+	lock     sync.Mutex
+	checksum [sha256.Size]byte
+	err      error
+	onReload func(err error)
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// =========================================================== 构造方法 ===========================================================
+
+// 从配置文件初始化全局日志，并按interval轮询配置文件的变化
+func InitZLogWithWatch(path string, interval time.Duration, options ...zap.Option) (*ZLogWatcher, error) {
+	w, err := newZLogWatcher(path, interval, options...)
+	if err != nil {
+		return nil, err
+	}
+	localZLog = w.z
+	return w, nil
+}
+
+// 从配置文件创建日志，并按interval轮询配置文件的变化
+func WatchConfigFile(path string, interval time.Duration, options ...zap.Option) (IZLog, *ZLogWatcher, error) {
+	w, err := newZLogWatcher(path, interval, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return w.z, w, nil
+}
+
+func newZLogWatcher(path string, interval time.Duration, options ...zap.Option) (*ZLogWatcher, error) {
+	if interval <= 0 {
+		interval = defaultReloadInterval
+	}
+	checksum, err := fileChecksum(path)
+	if err != nil {
+		return nil, err
+	}
+	z, err := newZLogFromFile(path, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &ZLogWatcher{
+		z:        z,
+		path:     path,
+		interval: interval,
+		options:  options,
+		checksum: checksum,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go w.watch()
+	return w, nil
+}
+
+// =========================================================== 接口方法 ===========================================================
+
+// 立即重新加载配置文件，失败时保留原有配置并返回错误
+func (w *ZLogWatcher) Reload() error {
+	w.lock.Lock()
+	checksum, err := fileChecksum(w.path)
+	if err != nil {
+		err = w.reloaded(err)
+	} else {
+		err = w.reload(checksum)
+	}
+	onReload := w.onReload
+	w.lock.Unlock()
+
+	if onReload != nil {
+		onReload(err)
+	}
+	return err
+}
+
+// 设置重新加载后的回调，err为nil表示加载成功
+func (w *ZLogWatcher) OnReload(f func(err error)) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.onReload = f
+}
+
+// 最近一次重新加载的错误
+func (w *ZLogWatcher) Err() error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	return w.err
+}
+
+// 停止监听配置文件
+func (w *ZLogWatcher) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.stop)
+	})
+	<-w.done
+}
+
+// =========================================================== 私有方法 ===========================================================
+
+func (w *ZLogWatcher) watch() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			w.check()
+		}
+	}
+}
+
+func (w *ZLogWatcher) check() {
+	w.lock.Lock()
+	checksum, err := fileChecksum(w.path)
+	if err != nil {
+		// 文件暂时不可读（如正在被替换）时只记录错误，下次轮询重试
+		if w.err != nil && w.err.Error() == err.Error() {
+			w.lock.Unlock()
+			return
+		}
+		err = w.reloaded(err)
+	} else if checksum == w.checksum {
+		w.lock.Unlock()
+		return
+	} else {
+		err = w.reload(checksum)
+	}
+	onReload := w.onReload
+	w.lock.Unlock()
+
+	if onReload != nil {
+		onReload(err)
+	}
+}
+
+func (w *ZLogWatcher) reload(checksum [sha256.Size]byte) error {
+	nz, err := newZLogFromFile(w.path, w.options...)
+	if err != nil {
+		// 记录校验和，避免同一份错误配置被反复加载
+		w.checksum = checksum
+		return w.reloaded(err)
+	}
+	w.checksum = checksum
+
+	if err := w.z.swap(nz).release(); err != nil {
+		w.z.Warnf("关闭旧日志文件失败：%v", err)
+	}
+	return w.reloaded(nil)
+}
+
+func (w *ZLogWatcher) reloaded(err error) error {
+	w.err = err
+	if err != nil {
+		w.z.Errorf("重新加载日志配置[%s]失败，继续使用原有配置：%v", w.path, err)
+	} else {
+		w.z.Infof("重新加载日志配置[%s]成功", w.path)
+	}
+	return err
+}
+
+func fileChecksum(path string) ([sha256.Size]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return [sha256.Size]byte{}, errors.Wrapf(err, "读取日志配置文件[%s]失败", path)
+	}
+	return sha256.Sum256(data), nil
+}
@@ -0,0 +1,153 @@
+// #############################################################################
+// # File: reload_test.go                                                      #
+// # Project: zlog                                                             #
+// # Created Date: 2026/10/17 20:12:40                                         #
+// # Author: realjf                                                            #
+// # -----                                                                     #
+// # Last Modified: 2026/10/17 20:30:23                                        #
+// # Modified By: realjf                                                       #
+// # -----                                                                     #
+// #                                                                           #
+// #############################################################################
+package zlog_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/realjf/zlog"
+)
+
+func writeReloadConfig(t *testing.T, path, logFile, level string) {
+	content := "loggers:\n  - log_mode: file\n    encoding: json\n    level: " + level + "\n    log_file: " + logFile + "\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readLogFile(t *testing.T, path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestWatchConfigFileReload(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "zlog.yaml")
+	logFile := filepath.Join(dir, "zlog.log")
+	writeReloadConfig(t, path, logFile, "info")
+
+	z, w, err := zlog.WatchConfigFile(path, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+
+	z.Debug("before reload")
+	writeReloadConfig(t, path, logFile, "debug")
+	if err := w.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	z.Debug("after reload")
+
+	if err := os.WriteFile(path, []byte("loggers:\n  - level: verbose\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Reload(); err == nil {
+		t.Fatal("expected reload error")
+	}
+	if w.Err() == nil {
+		t.Fatal("expected Err() to report the reload error")
+	}
+	z.Debug("after failed reload")
+
+	content := readLogFile(t, logFile)
+	if strings.Contains(content, "before reload") {
+		t.Errorf("debug line logged before reload: %s", content)
+	}
+	for _, msg := range []string{"after reload", "after failed reload"} {
+		if !strings.Contains(content, msg) {
+			t.Errorf("missing %q in %s", msg, content)
+		}
+	}
+}
+
+func TestWatchConfigFilePolling(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "zlog.yaml")
+	logFile := filepath.Join(dir, "zlog.log")
+	writeReloadConfig(t, path, logFile, "info")
+
+	z, w, err := zlog.WatchConfigFile(path, 10*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+
+	reloaded := make(chan error, 1)
+	w.OnReload(func(err error) {
+		select {
+		case reloaded <- err:
+		default:
+		}
+	})
+	writeReloadConfig(t, path, logFile, "debug")
+
+	select {
+	case err := <-reloaded:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("config change was not picked up")
+	}
+	z.Debug("after polling reload")
+	if content := readLogFile(t, logFile); !strings.Contains(content, "after polling reload") {
+		t.Errorf("missing debug line in %s", content)
+	}
+}
+
+// 使用go test -race运行，重新加载期间派生日志的并发写入不丢失
+func TestReloadConcurrentLogging(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "zlog.yaml")
+	logFile := filepath.Join(dir, "zlog.log")
+	writeReloadConfig(t, path, logFile, "info")
+
+	z, w, err := zlog.WatchConfigFile(path, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			child := z.WithPrefix("[child]")
+			for j := 0; j < 200; j++ {
+				child.Warn("concurrent")
+			}
+		}()
+	}
+	for i := 0; i < 5; i++ {
+		if err := w.Reload(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wg.Wait()
+	if err := z.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := strings.Count(readLogFile(t, logFile), "[child] concurrent"); n != 8*200 {
+		t.Fatalf("expected %d lines, got %d", 8*200, n)
+	}
+}
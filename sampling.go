@@ -0,0 +1,129 @@
+// #############################################################################
+// # File: sampling.go                                                         #
+// # Project: zlog                                                             #
+// # Created Date: 2026/10/17 20:38:30                                         #
+// # Author: realjf                                                            #
+// # -----                                                                     #
+// # Last Modified: 2026/10/17 20:38:30                                        #
+// # Modified By: realjf                                                       #
+// # -----                                                                     #
+// #                                                                           #
+// #############################################################################
+package zlog
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	samplingInitial    = 100
+	samplingThereafter = 100
+	samplingTick       = time.Second
+)
+
+type SamplingConfig struct {
+	Initial     int      `yaml:"initial" json:"initial"`           // 每个周期内相同级别和消息的日志先输出的条数，默认100
+	Thereafter  int      `yaml:"thereafter" json:"thereafter"`     // 之后每thereafter条输出一条，默认100
+	Tick        string   `yaml:"tick" json:"tick"`                 // 采样周期，默认1s
+	ExemptLevel LogLevel `yaml:"exempt_level" json:"exempt_level"` // 不参与采样的最低级别，如warn，默认所有级别都参与采样
+}
+
+// 日志采样器，记录被采样丢弃的日志条数
+type sampler struct {
+	initial    int
+	thereafter int
+	tick       time.Duration
+	exempt     zapcore.Level
+	sampled    atomic.Uint64
+}
+
+// 部分级别不参与采样的core
+type samplingCore struct {
+	zapcore.Core              // 不采样的core
+	sampled      zapcore.Core // 采样的core
+	exempt       zapcore.Level
+}
+
+// =========================================================== 构造方法 ===========================================================
+
+// 未配置采样时返回nil
+func newSampler(config *SamplingConfig) (*sampler, error) {
+	if config == nil {
+		return nil, nil
+	}
+	tick, err := parseSamplingConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	s := &sampler{
+		initial:    config.Initial,
+		thereafter: config.Thereafter,
+		tick:       tick,
+		exempt:     zapcore.InvalidLevel,
+	}
+	if s.initial <= 0 {
+		s.initial = samplingInitial
+	}
+	if s.thereafter <= 0 {
+		s.thereafter = samplingThereafter
+	}
+	if config.ExemptLevel != "" {
+		s.exempt = config.ExemptLevel.ZapLevel()
+	}
+	return s, nil
+}
+
+func parseSamplingConfig(config *SamplingConfig) (time.Duration, error) {
+	if config.Initial < 0 || config.Thereafter < 0 {
+		return 0, errors.WithMessage(ErrInvalidValue, "sampling.initial/sampling.thereafter不能为负数")
+	}
+	if config.ExemptLevel != "" && !config.ExemptLevel.valid() {
+		return 0, errors.WithMessagef(ErrInvalidLevel, "sampling.exempt_level取值非法：%s", config.ExemptLevel)
+	}
+	if config.Tick == "" {
+		return samplingTick, nil
+	}
+	tick, err := time.ParseDuration(config.Tick)
+	if err != nil || tick <= 0 {
+		return 0, errors.WithMessagef(ErrInvalidValue, "sampling.tick取值非法：%s", config.Tick)
+	}
+	return tick, nil
+}
+
+func (s *sampler) wrap(core zapcore.Core) zapcore.Core {
+	sampled := zapcore.NewSamplerWithOptions(core, s.tick, s.initial, s.thereafter, zapcore.SamplerHook(s.hook))
+	if s.exempt == zapcore.InvalidLevel {
+		return sampled
+	}
+	return &samplingCore{Core: core, sampled: sampled, exempt: s.exempt}
+}
+
+// 被采样丢弃的日志条数
+func (s *sampler) Sampled() uint64 {
+	return s.sampled.Load()
+}
+
+// =========================================================== 接口方法 ===========================================================
+
+func (c *samplingCore) With(fields []zapcore.Field) zapcore.Core {
+	return &samplingCore{Core: c.Core.With(fields), sampled: c.sampled.With(fields), exempt: c.exempt}
+}
+
+func (c *samplingCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if entry.Level >= c.exempt {
+		return c.Core.Check(entry, ce)
+	}
+	return c.sampled.Check(entry, ce)
+}
+
+// =========================================================== 私有方法 ===========================================================
+
+func (s *sampler) hook(_ zapcore.Entry, decision zapcore.SamplingDecision) {
+	if decision&zapcore.LogDropped != 0 {
+		s.sampled.Add(1)
+	}
+}
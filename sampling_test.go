@@ -0,0 +1,70 @@
+// #############################################################################
+// # File: sampling_test.go                                                    #
+// # Project: zlog                                                             #
+// # Created Date: 2026/10/17 20:38:54                                         #
+// # Author: realjf                                                            #
+// # -----                                                                     #
+// # Last Modified: 2026/10/17 20:38:54                                        #
+// # Modified By: realjf                                                       #
+// # -----                                                                     #
+// #                                                                           #
+// #############################################################################
+package zlog_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/realjf/zlog"
+)
+
+func TestSampling(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "zlog.log")
+	z := zlog.NewZLog([]*zlog.ZLogConfig{
+		{
+			LogMode:  "file",
+			Encoding: "json",
+			LogFile:  logFile,
+			Name:     "zlog",
+			Async:    &zlog.AsyncConfig{},
+			Sampling: &zlog.SamplingConfig{
+				Initial:     2,
+				Thereafter:  5,
+				Tick:        "1h",
+				ExemptLevel: "warn",
+			},
+		},
+	})
+	defer z.Close()
+
+	for i := 0; i < 12; i++ {
+		z.Info("sampled info")
+		z.Warn("exempt warn")
+	}
+	if err := z.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 前2条输出，之后每5条输出1条：第7、12条
+	if n := countLines(t, logFile, "sampled info"); n != 4 {
+		t.Fatalf("expected 4 info lines, got %d", n)
+	}
+	if n := countLines(t, logFile, "exempt warn"); n != 12 {
+		t.Fatalf("expected 12 warn lines, got %d", n)
+	}
+	if sampled := z.Stats()["zlog"].Sampled; sampled != 8 {
+		t.Fatalf("expected 8 sampled entries, got %d", sampled)
+	}
+}
+
+func TestSamplingInvalid(t *testing.T) {
+	for _, sampling := range []*zlog.SamplingConfig{
+		{Initial: -1},
+		{Tick: "soon"},
+		{ExemptLevel: "loud"},
+	} {
+		if _, err := zlog.NewZLogE([]*zlog.ZLogConfig{{Sampling: sampling}}); err == nil {
+			t.Errorf("%+v: expected error", *sampling)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+// #############################################################################
+// # File: signal.go                                                           #
+// # Project: zlog                                                             #
+// # Created Date: 2026/10/17 20:16:30                                         #
+// # Author: realjf                                                            #
+// # -----                                                                     #
+// # Last Modified: 2026/10/17 20:34:12                                        #
+// # Modified By: realjf                                                       #
+// # -----                                                                     #
+// #                                                                           #
+// #############################################################################
+package zlog
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+)
+
+type signalAction int
+
+const (
+	signalActionNone    signalAction = iota
+	signalActionReopen               // 重新打开日志文件，配合外部logrotate使用
+	signalActionDebug                // 所有日志记录器切换为debug级别
+	signalActionRestore              // 所有日志记录器恢复为配置的级别
+)
+
+type signalTarget interface {
+	reopenWriters() error
+	setDebug(debug bool)
+}
+
+// 信号处理器
+type SignalHandler struct {
+	loggers []IZLog
+	signals chan os.Signal
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// 监听信号并对loggers执行相应操作，未指定loggers时作用于全局日志
+//
+//	SIGHUP   重新打开所有文件日志（外部logrotate重命名文件后使用）
+//	SIGUSR1  所有日志记录器切换为debug级别
+//	SIGUSR2  所有日志记录器恢复为配置的级别
+//
+// 不支持上述信号的平台（如windows）上不做任何处理
+func HandleSignals(loggers ...IZLog) *SignalHandler {
+	h := &SignalHandler{
+		loggers: loggers,
+		signals: make(chan os.Signal, 1),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	if len(handledSignals) > 0 {
+		signal.Notify(h.signals, handledSignals...)
+	}
+	go h.run()
+	return h
+}
+
+// 停止监听信号
+func (h *SignalHandler) Stop() {
+	h.stopOnce.Do(func() {
+		signal.Stop(h.signals)
+		close(h.stop)
+	})
+	<-h.done
+}
+
+func (h *SignalHandler) run() {
+	defer close(h.done)
+
+	for {
+		select {
+		case <-h.stop:
+			return
+		case sig := <-h.signals:
+			h.handle(sig)
+		}
+	}
+}
+
+func (h *SignalHandler) handle(sig os.Signal) {
+	loggers := h.loggers
+	if len(loggers) == 0 {
+		loggers = []IZLog{ZLog()}
+	}
+	action := signalActionOf(sig)
+	for _, logger := range loggers {
+		target, ok := logger.(signalTarget)
+		if !ok {
+			continue
+		}
+		switch action {
+		case signalActionReopen:
+			if err := target.reopenWriters(); err != nil {
+				logger.Errorf("收到信号[%s]，重新打开日志文件失败：%v", sig, err)
+			} else {
+				logger.Infof("收到信号[%s]，重新打开日志文件", sig)
+			}
+		case signalActionDebug:
+			target.setDebug(true)
+			logger.Infof("收到信号[%s]，切换为debug级别", sig)
+		case signalActionRestore:
+			logger.Infof("收到信号[%s]，恢复为配置的日志级别", sig)
+			target.setDebug(false)
+		}
+	}
+}
+
+// =========================================================== 私有方法 ===========================================================
+
+// 关闭所有文件日志，下次写入时会按原路径重新打开
+func (z *zLog) reopenWriters() error {
+	return closeWriters(z.state.Load().writers)
+}
+
+func (z *zLog) setDebug(debug bool) {
+	z.lock.Lock()
+	defer z.lock.Unlock()
+
+	state := z.state.Load()
+	for name, atomicLevel := range state.levels {
+		z.cancelLevelOverride(name)
+		if debug {
+			atomicLevel.SetLevel(LogLevelDebug.ZapLevel())
+		} else {
+			atomicLevel.SetLevel(state.cfgs[name].Level.ZapLevel())
+		}
+	}
+}
@@ -0,0 +1,88 @@
+// #############################################################################
+// # File: signal_test.go                                                      #
+// # Project: zlog                                                             #
+// # Created Date: 2026/10/17 20:18:41                                         #
+// # Author: realjf                                                            #
+// # -----                                                                     #
+// # Last Modified: 2026/10/17 20:18:41                                        #
+// # Modified By: realjf                                                       #
+// # -----                                                                     #
+// #                                                                           #
+// #############################################################################
+
+//go:build !windows
+
+package zlog_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/realjf/zlog"
+)
+
+func waitFor(t *testing.T, cond func() bool) {
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met before deadline")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestHandleSignals(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "zlog.log")
+	z := zlog.NewZLog([]*zlog.ZLogConfig{
+		{
+			Level:    "info",
+			LogMode:  "file",
+			Encoding: "json",
+			LogFile:  logFile,
+			Name:     "zlog",
+		},
+	})
+	h := zlog.HandleSignals(z)
+	defer h.Stop()
+
+	if err := syscall.Kill(os.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, func() bool {
+		level, _ := z.GetLevel("zlog")
+		return level == "debug"
+	})
+	if err := syscall.Kill(os.Getpid(), syscall.SIGUSR2); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, func() bool {
+		level, _ := z.GetLevel("zlog")
+		return level == "info"
+	})
+
+	// 模拟logrotate重命名日志文件
+	z.Info("before rotate")
+	if err := os.Rename(logFile, logFile+".1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := syscall.Kill(os.Getpid(), syscall.SIGHUP); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, func() bool {
+		z.Info("after rotate")
+		data, err := os.ReadFile(logFile)
+		return err == nil && strings.Contains(string(data), "after rotate")
+	})
+
+	data, err := os.ReadFile(logFile + ".1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "before rotate") {
+		t.Errorf("missing line in rotated file: %s", data)
+	}
+}
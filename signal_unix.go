@@ -0,0 +1,34 @@
+// #############################################################################
+// # File: signal_unix.go                                                      #
+// # Project: zlog                                                             #
+// # Created Date: 2026/10/17 20:16:30                                         #
+// # Author: realjf                                                            #
+// # -----                                                                     #
+// # Last Modified: 2026/10/17 20:16:30                                        #
+// # Modified By: realjf                                                       #
+// # -----                                                                     #
+// #                                                                           #
+// #############################################################################
+
+//go:build !windows
+
+package zlog
+
+import (
+	"os"
+	"syscall"
+)
+
+var handledSignals = []os.Signal{syscall.SIGHUP, syscall.SIGUSR1, syscall.SIGUSR2}
+
+func signalActionOf(sig os.Signal) signalAction {
+	switch sig {
+	case syscall.SIGHUP:
+		return signalActionReopen
+	case syscall.SIGUSR1:
+		return signalActionDebug
+	case syscall.SIGUSR2:
+		return signalActionRestore
+	}
+	return signalActionNone
+}
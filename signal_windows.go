@@ -0,0 +1,24 @@
+// #############################################################################
+// # File: signal_windows.go                                                   #
+// # Project: zlog                                                             #
+// # Created Date: 2026/10/17 20:16:30                                         #
+// # Author: realjf                                                            #
+// # -----                                                                     #
+// # Last Modified: 2026/10/17 20:16:30                                        #
+// # Modified By: realjf                                                       #
+// # -----                                                                     #
+// #                                                                           #
+// #############################################################################
+
+//go:build windows
+
+package zlog
+
+import "os"
+
+// windows不支持SIGHUP/SIGUSR1/SIGUSR2
+var handledSignals []os.Signal
+
+func signalActionOf(sig os.Signal) signalAction {
+	return signalActionNone
+}
@@ -0,0 +1,83 @@
+// #############################################################################
+// # File: stack_encoder.go                                                    #
+// # Project: zlog                                                             #
+// # Created Date: 2026/10/17 20:36:37                                         #
+// # Author: realjf                                                            #
+// # -----                                                                     #
+// # Last Modified: 2026/10/17 20:56:52                                        #
+// # Modified By: realjf                                                       #
+// # -----                                                                     #
+// #                                                                           #
+// #############################################################################
+package zlog
+
+import (
+	"strings"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// 裁剪堆栈的编码器，限制栈帧数量并过滤指定前缀的函数
+//
+// 在编码时裁剪而不是包装core：zapcore.NewTee写入时不检查子core的级别，包装Tee会使同一条日志写入多次
+type stackEncoder struct {
+	zapcore.Encoder
+	depth int      // 最多保留的栈帧数，0表示不限制
+	skip  []string // 跳过函数名以这些前缀开头的栈帧
+}
+
+// =========================================================== 构造方法 ===========================================================
+
+// 未配置堆栈深度和过滤规则时直接返回enc
+func newStackEncoder(enc zapcore.Encoder, config *ZLogConfig) zapcore.Encoder {
+	if config.StacktraceDepth <= 0 && len(config.StacktraceSkip) == 0 {
+		return enc
+	}
+	return &stackEncoder{Encoder: enc, depth: config.StacktraceDepth, skip: config.StacktraceSkip}
+}
+
+// =========================================================== 接口方法 ===========================================================
+
+func (e *stackEncoder) Clone() zapcore.Encoder {
+	return &stackEncoder{Encoder: e.Encoder.Clone(), depth: e.depth, skip: e.skip}
+}
+
+func (e *stackEncoder) EncodeEntry(entry zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	if entry.Stack != "" {
+		entry.Stack = e.trim(entry.Stack)
+	}
+	return e.Encoder.EncodeEntry(entry, fields)
+}
+
+// =========================================================== 私有方法 ===========================================================
+
+// zap的堆栈每帧占两行：函数名和"\t文件:行号"
+func (e *stackEncoder) trim(stack string) string {
+	lines := strings.Split(stack, "\n")
+	frames := make([]string, 0, len(lines))
+	kept := 0
+	for i := 0; i < len(lines); i += 2 {
+		if e.depth > 0 && kept >= e.depth {
+			break
+		}
+		if e.skipped(lines[i]) {
+			continue
+		}
+		frames = append(frames, lines[i])
+		if i+1 < len(lines) {
+			frames = append(frames, lines[i+1])
+		}
+		kept++
+	}
+	return strings.Join(frames, "\n")
+}
+
+func (e *stackEncoder) skipped(function string) bool {
+	for _, prefix := range e.skip {
+		if strings.HasPrefix(function, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,139 @@
+// #############################################################################
+// # File: stack_encoder_test.go                                               #
+// # Project: zlog                                                             #
+// # Created Date: 2026/10/17 20:36:53                                         #
+// # Author: realjf                                                            #
+// # -----                                                                     #
+// # Last Modified: 2026/10/17 20:57:25                                        #
+// # Modified By: realjf                                                       #
+// # -----                                                                     #
+// #                                                                           #
+// #############################################################################
+package zlog_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/realjf/zlog"
+)
+
+// 日志消息到堆栈的映射
+func readStacktraces(t *testing.T, path string) map[string]string {
+	stacks := make(map[string]string)
+	for _, entry := range readEntries(t, path) {
+		stacks[entry.Msg] = entry.Stacktrace
+	}
+	return stacks
+}
+
+func TestStacktraceLevel(t *testing.T) {
+	dir := t.TempDir()
+	z := zlog.NewZLog([]*zlog.ZLogConfig{
+		{
+			Level:    "debug",
+			LogMode:  "file",
+			Encoding: "json",
+			LogFile:  filepath.Join(dir, "default.log"),
+			Name:     "default",
+		},
+		{
+			Level:           "debug",
+			LogMode:         "file|console",
+			Encoding:        "json",
+			LogFile:         filepath.Join(dir, "warn.log"),
+			Name:            "warn",
+			StacktraceLevel: "warn",
+			StacktraceDepth: 2,
+			StacktraceSkip:  []string{"testing."},
+		},
+	})
+	defer z.Close()
+
+	both := z.WithName("default", "warn")
+	both.Debug("debug")
+	both.Warn("warn")
+	both.Error("error")
+	if err := z.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 默认只有error及以上级别记录堆栈，与日志级别无关
+	stacks := readStacktraces(t, filepath.Join(dir, "default.log"))
+	if stacks["debug"] != "" || stacks["warn"] != "" || stacks["error"] == "" {
+		t.Fatalf("unexpected stacktraces with default level: %v", stacks)
+	}
+
+	stacks = readStacktraces(t, filepath.Join(dir, "warn.log"))
+	if stacks["debug"] != "" || stacks["warn"] == "" {
+		t.Fatalf("unexpected stacktraces with warn level: %v", stacks)
+	}
+	for msg, stack := range stacks {
+		if stack == "" {
+			continue
+		}
+		if frames := strings.Count(stack, "\n\t"); frames > 2 {
+			t.Errorf("%s: expected at most 2 frames, got %d", msg, frames)
+		}
+		if strings.Contains(stack, "testing.") {
+			t.Errorf("%s: expected testing frames to be skipped: %s", msg, stack)
+		}
+	}
+
+	if _, err := zlog.NewZLogE([]*zlog.ZLogConfig{{StacktraceLevel: "loud", StacktraceDepth: -1}}); err == nil {
+		t.Fatal("expected validation error")
+	}
+}
+
+// 异步队列丢弃模式下core为Tee，裁剪堆栈不能使同一条日志写入多次
+func TestStacktraceAsyncDrop(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "zlog.log")
+	z := zlog.NewZLog([]*zlog.ZLogConfig{
+		{
+			LogMode:         "file",
+			Encoding:        "json",
+			LogFile:         logFile,
+			StacktraceDepth: 3,
+			Async:           &zlog.AsyncConfig{OnFull: "drop"},
+		},
+	})
+	z.Info("info")
+	z.Error("error")
+	z.Close()
+
+	entries := readEntries(t, logFile)
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if frames := strings.Count(entries[1].Stacktrace, "\n\t"); entries[1].Stacktrace == "" || frames > 3 {
+		t.Fatalf("expected at most 3 frames, got %d", frames)
+	}
+}
+
+// options.add_stacktrace作为未设置stacktrace_level的日志记录器的默认值
+func TestStacktraceLevelOption(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "zlog.yaml")
+	content := "loggers:\n" +
+		"  - name: fatal\n    log_mode: file\n    encoding: json\n    stacktrace_level: fatal\n    log_file: " + filepath.Join(dir, "fatal.log") + "\n" +
+		"  - name: debug\n    log_mode: file\n    encoding: json\n    log_file: " + filepath.Join(dir, "debug.log") + "\n" +
+		"options:\n  add_stacktrace: debug\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	z, err := zlog.NewZLogFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	z.WithName("fatal", "debug").Info("info")
+	z.Close()
+
+	if stacks := readStacktraces(t, filepath.Join(dir, "fatal.log")); stacks["info"] != "" {
+		t.Errorf("expected no stacktrace with stacktrace_level fatal: %v", stacks)
+	}
+	if stacks := readStacktraces(t, filepath.Join(dir, "debug.log")); stacks["info"] == "" {
+		t.Errorf("expected stacktrace from options.add_stacktrace: %v", stacks)
+	}
+}
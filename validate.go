@@ -0,0 +1,166 @@
+// #############################################################################
+// # File: validate.go                                                         #
+// # Project: zlog                                                             #
+// # Created Date: 2026/10/17 20:11:08                                         #
+// # Author: realjf                                                            #
+// # -----                                                                     #
+// # Last Modified: 2026/10/17 20:39:04                                        #
+// # Modified By: realjf                                                       #
+// # -----                                                                     #
+// #                                                                           #
+// #############################################################################
+package zlog
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/realjf/zlog/utils/fileutil"
+)
+
+// 校验日志配置，返回汇总后的ConfigErrors
+//
+// 校验内容：配置列表为空、级别/模式/编码取值、负数大小、名称重复以及文件模式下日志文件是否可写
+func ValidateConfigs(configs []*ZLogConfig) error {
+	return validateConfigs(configs, true)
+}
+
+func validateConfigs(configs []*ZLogConfig, checkPath bool) error {
+	if len(configs) == 0 {
+		return ConfigErrors{{Index: -1, Err: ErrNoConfig}}
+	}
+
+	var errs ConfigErrors
+	names := make(map[string]int)
+	for i, config := range configs {
+		if config == nil {
+			errs = append(errs, &ConfigError{Index: i, Err: ErrNilConfig})
+			continue
+		}
+		errs = append(errs, validateConfig(i, config)...)
+		if j, ok := names[config.Name]; ok {
+			errs = append(errs, &ConfigError{Index: i, Name: config.Name, Field: "name", Value: config.Name, Err: fmt.Errorf("%w: 与loggers[%d]重复", ErrDuplicateName, j)})
+		} else {
+			names[config.Name] = i
+		}
+		if checkPath && validLogMode(config.LogMode) {
+			if file, _ := parseLogMode(config.LogMode); file {
+				logFile := config.LogFile
+				if pattern := newFilePattern(config); pattern != nil {
+					logFile = pattern.expand(time.Now())
+				}
+				if err := checkWritable(logFile); err != nil {
+					errs = append(errs, &ConfigError{Index: i, Name: config.Name, Field: "log_file", Value: config.LogFile, Err: fmt.Errorf("%w: %v", ErrUnwritablePath, err)})
+				}
+			}
+		}
+	}
+	return errs.orNil()
+}
+
+func validateConfig(i int, config *ZLogConfig) (errs ConfigErrors) {
+	newErr := func(field, value string, err error) *ConfigError {
+		return &ConfigError{Index: i, Name: config.Name, Field: field, Value: value, Err: err}
+	}
+	if config.Level != "" && !config.Level.valid() {
+		errs = append(errs, newErr("level", config.Level.String(), ErrInvalidLevel))
+	}
+	if !validCallerEncoding(config.CallerEncoding) {
+		errs = append(errs, newErr("caller_encoding", config.CallerEncoding, ErrInvalidValue))
+	}
+	if config.CallerSkip < 0 {
+		errs = append(errs, newErr("caller_skip", strconv.Itoa(config.CallerSkip), ErrInvalidValue))
+	}
+	if config.StacktraceLevel != "" && !config.StacktraceLevel.valid() {
+		errs = append(errs, newErr("stacktrace_level", config.StacktraceLevel.String(), ErrInvalidLevel))
+	}
+	if config.StacktraceDepth < 0 {
+		errs = append(errs, newErr("stacktrace_depth", strconv.Itoa(config.StacktraceDepth), ErrInvalidValue))
+	}
+	if !validLogMode(config.LogMode) {
+		errs = append(errs, newErr("log_mode", config.LogMode, ErrInvalidLogMode))
+	}
+	if !validEncoding(config.Encoding) {
+		errs = append(errs, newErr("encoding", config.Encoding, ErrInvalidEncoding))
+	}
+	if config.MaxSize < 0 {
+		errs = append(errs, newErr("max_size", strconv.Itoa(config.MaxSize), ErrInvalidValue))
+	}
+	if config.MaxAge < 0 {
+		errs = append(errs, newErr("max_age", strconv.Itoa(config.MaxAge), ErrInvalidValue))
+	}
+	if config.MaxBackups < 0 {
+		errs = append(errs, newErr("max_backups", strconv.Itoa(config.MaxBackups), ErrInvalidValue))
+	}
+	if _, err := parseRotateInterval(config.RotateInterval); err != nil {
+		errs = append(errs, newErr("rotate_interval", config.RotateInterval, ErrInvalidValue))
+	}
+	if _, err := parseRotateAt(config.RotateAt); err != nil {
+		errs = append(errs, newErr("rotate_at", config.RotateAt, ErrInvalidValue))
+	}
+	if config.Async != nil {
+		if _, err := parseAsyncConfig(config.Async); err != nil {
+			errs = append(errs, newErr("async", fmt.Sprintf("%+v", *config.Async), err))
+		}
+	}
+	if config.Sampling != nil {
+		if _, err := parseSamplingConfig(config.Sampling); err != nil {
+			errs = append(errs, newErr("sampling", fmt.Sprintf("%+v", *config.Sampling), err))
+		}
+	}
+	return
+}
+
+func validLogMode(logMode string) bool {
+	for _, mode := range strings.Split(logMode, "|") {
+		if mode = strings.TrimSpace(mode); mode != "" && mode != logModeFile && mode != logModeStdout {
+			return false
+		}
+	}
+	return true
+}
+
+func validCallerEncoding(encoding string) bool {
+	switch encoding {
+	case "", callerEncodingShort, callerEncodingFull, callerEncodingFunction:
+		return true
+	}
+	return false
+}
+
+func validEncoding(encoding string) bool {
+	return encoding == "" || encoding == logEncodingConsole || encoding == logEncodingJson
+}
+
+// 解析日志模式，未指定文件模式时默认输出到控制台
+func parseLogMode(logMode string) (file bool, console bool) {
+	for _, mode := range strings.Split(logMode, "|") {
+		switch strings.TrimSpace(mode) {
+		case logModeFile:
+			file = true
+		case logModeStdout:
+			console = true
+		}
+	}
+	return file, console || !file
+}
+
+// 检查日志文件所在目录能否创建、文件能否以追加方式打开
+func checkWritable(logFile string) error {
+	path, err := filepath.Abs(logFile)
+	if err != nil {
+		return err
+	}
+	if err := fileutil.MkdirIfNecessary(filepath.Dir(path)); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,82 @@
+// #############################################################################
+// # File: validate_test.go                                                    #
+// # Project: zlog                                                             #
+// # Created Date: 2026/10/17 20:11:39                                         #
+// # Author: realjf                                                            #
+// # -----                                                                     #
+// # Last Modified: 2026/10/17 20:11:39                                        #
+// # Modified By: realjf                                                       #
+// # -----                                                                     #
+// #                                                                           #
+// #############################################################################
+package zlog_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/realjf/zlog"
+)
+
+func TestNewZLogE(t *testing.T) {
+	dir := t.TempDir()
+	z, err := zlog.NewZLogE([]*zlog.ZLogConfig{
+		{
+			LogMode:  "file|console",
+			Encoding: "json",
+			LogFile:  filepath.Join(dir, "zlog.log"),
+			Name:     "zlog",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	z.Infof("hello %s", "realjf")
+}
+
+func TestNewZLogEInvalid(t *testing.T) {
+	notDir := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(notDir, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := zlog.NewZLogE([]*zlog.ZLogConfig{
+		{Name: "zlog", Level: "verbose", Encoding: "xml"},
+		{Name: "zlog", LogMode: "syslog", MaxSize: -1},
+		{Name: "zlog2", LogMode: "file", LogFile: filepath.Join(notDir, "zlog.log")},
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	t.Log(err)
+
+	var errs zlog.ConfigErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected ConfigErrors, got %T", err)
+	}
+	if len(errs) != 6 {
+		t.Fatalf("expected 6 errors, got %d", len(errs))
+	}
+	for _, target := range []error{
+		zlog.ErrInvalidLevel,
+		zlog.ErrInvalidEncoding,
+		zlog.ErrInvalidLogMode,
+		zlog.ErrInvalidValue,
+		zlog.ErrDuplicateName,
+		zlog.ErrUnwritablePath,
+	} {
+		if !errors.Is(err, target) {
+			t.Errorf("expected %v", target)
+		}
+	}
+
+	if _, err := zlog.NewZLogE(nil); !errors.Is(err, zlog.ErrNoConfig) {
+		t.Fatalf("expected ErrNoConfig, got %v", err)
+	}
+	if err := zlog.InitZLogE([]*zlog.ZLogConfig{nil}); !errors.Is(err, zlog.ErrNilConfig) {
+		t.Fatalf("expected ErrNilConfig, got %v", err)
+	}
+}
@@ -4,7 +4,7 @@
 // # Created Date: 2024/10/08 15:18:55                                         #
 // # Author: realjf                                                            #
 // # -----                                                                     #
-// # Last Modified: 2025/06/10 20:36:33                                        #
+// # Last Modified: 2026/10/17 20:56:52                                        #
 // # Modified By: realjf                                                       #
 // # -----                                                                     #
 // #                                                                           #
@@ -13,18 +13,19 @@ package zlog
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"log"
+	"os"
 	"path/filepath"
-	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/pkg/errors"
+	"go.uber.org/multierr"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
-	"gopkg.in/natefinch/lumberjack.v2"
-
-	"github.com/realjf/zlog/utils/fileutil"
 )
 
 const (
@@ -35,6 +36,14 @@ const (
 	logEncodingJson    = "json"
 	logModeFile        = "file"
 	logModeStdout      = "console"
+
+	callerEncodingShort    = "short"
+	callerEncodingFull     = "full"
+	callerEncodingFunction = "function"
+
+	// 打印日志时zLog的包装方法在调用方和zap之间多出的栈帧：
+	// Info -> log -> withName -> 回调函数 -> zap.Logger.Check
+	wrapperCallerSkip = 4
 )
 
 type IZLog interface {
@@ -46,9 +55,24 @@ type IZLog interface {
 	Warnf(template string, args ...interface{})
 	Error(msg string, fields ...zapcore.Field)
 	Errorf(template string, args ...interface{})
+	DPanic(msg string, fields ...zapcore.Field)
+	DPanicf(template string, args ...interface{})
+	Panic(msg string, fields ...zapcore.Field)
+	Panicf(template string, args ...interface{})
 	Fatal(msg string, fields ...zapcore.Field)
 	Fatalf(template string, args ...interface{})
 
+	Debugw(msg string, keysAndValues ...interface{})
+	Infow(msg string, keysAndValues ...interface{})
+	Warnw(msg string, keysAndValues ...interface{})
+	Errorw(msg string, keysAndValues ...interface{})
+	DPanicw(msg string, keysAndValues ...interface{})
+	Panicw(msg string, keysAndValues ...interface{})
+	Fatalw(msg string, keysAndValues ...interface{})
+
+	// 附加ctx中的链路追踪等字段，推荐使用Ctx(ctx)代替以下*WithTrace方法
+	Ctx(ctx context.Context) IZLog
+
 	DebugWithTrace(ctx context.Context, msg string, fields ...zapcore.Field)
 	DebugfWithTrace(ctx context.Context, template string, args ...interface{})
 	InfoWithTrace(ctx context.Context, msg string, fields ...zapcore.Field)
@@ -57,19 +81,42 @@ type IZLog interface {
 	WarnfWithTrace(ctx context.Context, template string, args ...interface{})
 	ErrorWithTrace(ctx context.Context, msg string, fields ...zapcore.Field)
 	ErrorfWithTrace(ctx context.Context, template string, args ...interface{})
+	DPanicWithTrace(ctx context.Context, msg string, fields ...zapcore.Field)
+	DPanicfWithTrace(ctx context.Context, template string, args ...interface{})
+	PanicWithTrace(ctx context.Context, msg string, fields ...zapcore.Field)
+	PanicfWithTrace(ctx context.Context, template string, args ...interface{})
 	FatalWithTrace(ctx context.Context, msg string, fields ...zapcore.Field)
 	FatalfWithTrace(ctx context.Context, template string, args ...interface{})
+	DebugwWithTrace(ctx context.Context, msg string, keysAndValues ...interface{})
+	InfowWithTrace(ctx context.Context, msg string, keysAndValues ...interface{})
+	WarnwWithTrace(ctx context.Context, msg string, keysAndValues ...interface{})
+	ErrorwWithTrace(ctx context.Context, msg string, keysAndValues ...interface{})
+	DPanicwWithTrace(ctx context.Context, msg string, keysAndValues ...interface{})
+	PanicwWithTrace(ctx context.Context, msg string, keysAndValues ...interface{})
+	FatalwWithTrace(ctx context.Context, msg string, keysAndValues ...interface{})
 
 	WithPrefix(prefix string) IZLog
 	WithName(name ...string) IZLog
+	With(fields ...zapcore.Field) IZLog
+	WithOptions(opts ...Option) IZLog
+
+	SetLevel(name string, level LogLevel) error
+	SetLevelFor(name string, level LogLevel, duration time.Duration) error
+	GetLevel(name string) (LogLevel, error)
+	Stats() map[string]LoggerStats
 
 	GetZCore(name string) *zap.Logger
+
+	Sync() error
+	Close() error
+	Shutdown(ctx context.Context) error
 }
 
 var localZLog *zLog
 
+// 导入包时创建的默认日志不读取环境变量，避免导入时因环境变量创建目录或panic
 func init() {
-	localZLog = newZLog([]*ZLogConfig{
+	localZLog = mustNewZLog([]*ZLogConfig{
 		{
 			Compress: true,
 		},
@@ -84,417 +131,737 @@ func ZLog() IZLog {
 }
 
 func InitZLog(configs []*ZLogConfig, options ...zap.Option) {
-	localZLog = newZLog(configs, options...)
+	applyEnvOverrides(configs)
+	localZLog = mustNewZLog(configs, options...)
+}
+
+// 校验配置并初始化全局日志，失败时保留原有日志并返回错误
+func InitZLogE(configs []*ZLogConfig, options ...zap.Option) error {
+	z, err := newZLogE(configs, options...)
+	if err != nil {
+		return err
+	}
+	localZLog = z
+	return nil
 }
 
 // =========================================================== 结构体 ===========================================================
 
 type ZLogConfig struct {
-	Level      LogLevel `yaml:"level"`       // 日志级别： debug|info|warn|error|fatal
-	LogMode    string   `yaml:"log_mode"`    // 日志模式 console|file
-	MaxSize    int      `yaml:"max_size"`    // 单日志文件最大字节/M
-	MaxAge     int      `yaml:"max_age"`     // 日志文件最大存活天数
-	MaxBackups int      `yaml:"max_backups"` // 日志文件最大数
-	Compress   bool     `yaml:"compress"`    // 是否启用压缩
-	Encoding   string   `yaml:"encoding"`    // 日志编码 console|json
-	LogFile    string   `yaml:"log_file"`    // 日志文件路径
-	Name       string   `yaml:"name"`        // 日志名称
-	Default    bool     `yaml:"default"`     // 默认日志记录器
+	Level      LogLevel `yaml:"level" json:"level"`             // 日志级别： debug|info|warn|error|dpanic|panic|fatal
+	LogMode    string   `yaml:"log_mode" json:"log_mode"`       // 日志模式 console|file
+	MaxSize    int      `yaml:"max_size" json:"max_size"`       // 单日志文件最大字节/M
+	MaxAge     int      `yaml:"max_age" json:"max_age"`         // 日志文件最大存活天数
+	MaxBackups int      `yaml:"max_backups" json:"max_backups"` // 日志文件最大数
+	Compress   bool     `yaml:"compress" json:"compress"`       // 是否启用压缩
+	Encoding   string   `yaml:"encoding" json:"encoding"`       // 日志编码 console|json
+	LogFile    string   `yaml:"log_file" json:"log_file"`       // 日志文件路径，支持{service}|{hostname}|{pid}|{name}以及%Y%m%d%H%M%S占位符
+	Name       string   `yaml:"name" json:"name"`               // 日志名称
+	Service    string   `yaml:"service" json:"service"`         // 服务名，用于日志文件名中的{service}占位符，默认为程序名
+	Default    bool     `yaml:"default" json:"default"`         // 默认日志记录器
+
+	Development bool `yaml:"development" json:"development"` // 开发模式，DPanic级别的日志会在写入后panic
+
+	CallerEncoding string `yaml:"caller_encoding" json:"caller_encoding"` // 调用位置编码 short|full|function，默认short
+	CallerSkip     int    `yaml:"caller_skip" json:"caller_skip"`         // 额外跳过的栈帧数，用于再次封装zlog的库
+
+	StacktraceLevel LogLevel `yaml:"stacktrace_level" json:"stacktrace_level"` // 记录堆栈的最低级别，默认error
+	StacktraceDepth int      `yaml:"stacktrace_depth" json:"stacktrace_depth"` // 堆栈最多保留的栈帧数，0表示不限制
+	StacktraceSkip  []string `yaml:"stacktrace_skip" json:"stacktrace_skip"`   // 跳过函数名以这些前缀开头的栈帧，如runtime.
+
+	RotateInterval string           `yaml:"rotate_interval" json:"rotate_interval"` // 按时间切割日志 hourly|daily|时长(如6h)，与按大小切割同时生效
+	RotateAt       string           `yaml:"rotate_at" json:"rotate_at"`             // 按时间切割的时间点偏移 HH:MM，如daily配合02:00表示每天2点切割
+	Clock          func() time.Time `yaml:"-" json:"-"`                             // 按时间切割使用的时钟，默认time.Now
+
+	Async    *AsyncConfig    `yaml:"async" json:"async"`       // 异步写入配置，nil表示同步写入
+	Sampling *SamplingConfig `yaml:"sampling" json:"sampling"` // 日志采样配置，nil表示不采样
 }
 
-type zLog struct {
-	loggers map[string]*zap.Logger
-	cfgs    map[string]*ZLogConfig
+// 日志记录器的统计信息
+type LoggerStats struct {
+	Dropped uint64 `json:"dropped"` // 异步写入队列满时被丢弃的日志条数，同时输出到控制台和文件时分别计数
+	Sampled uint64 `json:"sampled"` // 被采样丢弃的日志条数
+}
+
+// 日志记录器及其资源，创建后不再修改，重新加载时整体替换
+type zLogState struct {
+	loggers  map[string]*zap.Logger
+	wrapped  map[string]*zap.Logger        // 跳过zLog包装方法栈帧的记录器，打印日志时使用
+	sugars   map[string]*zap.SugaredLogger // 缓存的SugaredLogger，避免每次打印时创建
+	defaults []string                      // 默认日志记录器名称
+	cfgs     map[string]*ZLogConfig
+	levels   map[string]zap.AtomicLevel // 各日志记录器的级别，可在运行时修改
+
+	writers []*fileWriter          // 文件日志写入器，重新加载时需要关闭
+	queues  map[string]*asyncQueue // 异步写入队列，重新加载时需要关闭
+
+	samplers map[string]*sampler // 日志采样器
+
+	fatalHook *fatalWriteHook // GetZCore返回的记录器共用的FatalHook
+}
+
+// 日志的共享状态，派生日志与创建它的日志共享同一个zLogRoot
+type zLogRoot struct {
+	state   atomic.Pointer[zLogState] // 打印日志时无锁读取
 	options []zap.Option
 
-	lock        sync.Mutex
-	usedLoggers map[string]*zap.Logger
+	lock      sync.Mutex                // 保护state的替换和overrides
+	overrides map[string]*levelOverride // 临时修改的日志级别
 
-	prefix string
+	closed atomic.Bool // 已关闭
+}
+
+// 日志句柄，只记录相对于共享状态的差异，派生时不会重新创建记录器和写入器
+type zLog struct {
+	*zLogRoot
+
+	names  []string    // 使用的日志记录器，为空时使用默认日志记录器
+	prefix string      // 日志前缀
+	fields []zap.Field // 附加字段
 }
 
 // =========================================================== 构造方法 ===========================================================
 
 func NewZLog(configs []*ZLogConfig, options ...zap.Option) IZLog {
+	applyEnvOverrides(configs)
+	return mustNewZLog(configs, options...)
+}
+
+// 校验配置并创建日志，配置非法时返回ConfigErrors
+func NewZLogE(configs []*ZLogConfig, options ...zap.Option) (IZLog, error) {
+	z, err := newZLogE(configs, options...)
+	if err != nil {
+		return nil, err
+	}
+	return z, nil
+}
+
+func newZLogE(configs []*ZLogConfig, options ...zap.Option) (*zLog, error) {
+	if err := ApplyEnvOverrides(configs); err != nil {
+		return nil, err
+	}
+	if err := ValidateConfigs(configs); err != nil {
+		return nil, err
+	}
 	return newZLog(configs, options...)
 }
 
-func newZLog(configs []*ZLogConfig, options ...zap.Option) *zLog {
+func mustNewZLog(configs []*ZLogConfig, options ...zap.Option) *zLog {
+	z, err := newZLog(configs, options...)
+	if err != nil {
+		log.Panicf("创建日志失败：%+v\n", err)
+	}
+	return z
+}
+
+func newZLog(configs []*ZLogConfig, options ...zap.Option) (*zLog, error) {
+	if len(configs) == 0 {
+		return nil, ErrNoConfig
+	}
+
 	cfgs := make(map[string]*ZLogConfig)
+	atomicLevels := make(map[string]zap.AtomicLevel)
 	loggers := make(map[string]*zap.Logger)
-	usedLoggers := make(map[string]*zap.Logger, 0)
+	wrapped := make(map[string]*zap.Logger)
+	sugars := make(map[string]*zap.SugaredLogger)
+	defaults := make([]string, 0)
+	writers := make([]*fileWriter, 0)
+	queues := make(map[string]*asyncQueue)
+	samplers := make(map[string]*sampler)
+	root := &zLogRoot{options: options}
+	fatalHook := &fatalWriteHook{}
+	fatalHook.root.Store(root)
+	// 放在options之前，可被options中的zap.WithFatalHook覆盖
+	loggerOptions := append([]zap.Option{zap.WithFatalHook(fatalHook)}, options...)
+	for i, config := range configs {
+		if config == nil {
+			return nil, &ConfigError{Index: i, Err: ErrNilConfig}
+		}
+		// 非法的日志级别不再按debug处理
+		if config.Level != "" && !config.Level.valid() {
+			return nil, &ConfigError{Index: i, Name: config.Name, Field: "level", Value: config.Level.String(), Err: ErrInvalidLevel}
+		}
+		if config.StacktraceLevel != "" && !config.StacktraceLevel.valid() {
+			return nil, &ConfigError{Index: i, Name: config.Name, Field: "stacktrace_level", Value: config.StacktraceLevel.String(), Err: ErrInvalidLevel}
+		}
+	}
 	for _, config := range configs {
-		var logger *zap.Logger
-
 		var err error
 		config.LogFile, err = filepath.Abs(config.LogFile)
 		if err != nil {
-			log.Panicf("获取日志文件绝对路径失败：%v\n", err.Error())
+			return nil, errors.Wrapf(err, "获取日志文件[%s]绝对路径失败", config.LogFile)
+		}
+
+		level := zap.NewAtomicLevelAt(config.Level.ZapLevel())
+
+		sampler, err := newSampler(config.Sampling)
+		if err != nil {
+			(&zLogState{writers: writers, queues: queues}).release()
+			return nil, err
 		}
 
-		if strings.Contains(config.LogMode, logModeFile) && strings.Contains(config.LogMode, logModeStdout) {
-			logger = newZLogWithFileAndConsole(config, options...)
-		} else if config.LogMode == logModeFile {
-			logger = newZLogWithFile(config, options...)
-		} else {
-			logger = newZLogWithConsole(config, options...)
+		logger, writer, queue, err := newLogger(config, level, sampler, loggerOptions...)
+		if err != nil {
+			(&zLogState{writers: writers, queues: queues}).release()
+			return nil, err
+		}
+		if writer != nil {
+			writers = append(writers, writer)
+		}
+		if queue != nil {
+			queues[config.Name] = queue
+		}
+		if sampler != nil {
+			samplers[config.Name] = sampler
 		}
 		cfgs[config.Name] = config
+		atomicLevels[config.Name] = level
 		loggers[config.Name] = logger
+		// 由zLog在写入所有选中的日志记录器后统一panic或执行FatalHook，GetZCore返回的记录器保持zap原有的行为
+		wrapped[config.Name] = logger.WithOptions(
+			zap.AddCallerSkip(wrapperCallerSkip+config.CallerSkip),
+			zap.WithPanicHook(noopHook{}),
+			zap.WithFatalHook(noopHook{}),
+		)
+		sugars[config.Name] = wrapped[config.Name].Sugar()
 		if config.Default {
-			usedLoggers[config.Name] = logger
+			defaults = append(defaults, config.Name)
 		}
 	}
 
-	if len(usedLoggers) == 0 {
+	if len(defaults) == 0 {
 		// use first logger as default
 		cfgs[configs[0].Name].Default = true
-		usedLoggers[configs[0].Name] = loggers[configs[0].Name]
+		defaults = append(defaults, configs[0].Name)
 	}
 
-	return &zLog{
-		loggers:     loggers,
-		cfgs:        cfgs,
-		usedLoggers: usedLoggers,
-		options:     options,
-	}
+	root.state.Store(&zLogState{
+		loggers:  loggers,
+		wrapped:  wrapped,
+		sugars:   sugars,
+		defaults: defaults,
+		cfgs:     cfgs,
+		levels:   atomicLevels,
+		writers:  writers,
+		queues:   queues,
+		samplers: samplers,
+
+		fatalHook: fatalHook,
+	})
+	return &zLog{zLogRoot: root}, nil
 }
 
-func newZLogWithConsole(config *ZLogConfig, options ...zap.Option) (logger *zap.Logger) {
-	conf := zap.Config{
-		Level:            zap.NewAtomicLevelAt(config.Level.toZapLevel()),
-		EncoderConfig:    newEncoderConfig(),
-		Encoding:         logEncodingConsole,
-		OutputPaths:      []string{"stdout"},
-		ErrorOutputPaths: []string{"stderr"},
-	}
-	if config.Encoding == logEncodingJson {
-		conf.Encoding = logEncodingJson
+// 根据日志模式创建控制台和文件core，启用异步写入时编码后的日志由asyncQueue写入
+func newLogger(config *ZLogConfig, level zap.AtomicLevel, sampler *sampler, options ...zap.Option) (logger *zap.Logger, writer *fileWriter, queue *asyncQueue, err error) {
+	if config.Async != nil {
+		if queue, err = newAsyncQueue(config.Async); err != nil {
+			return nil, nil, nil, err
+		}
 	}
-	logger, err := conf.Build(options...)
-	if err != nil {
-		log.Printf("Zap日志创建失败，使用NewExample创建\n")
-		logger = zap.NewExample(options...)
+	newCore := func(ws zapcore.WriteSyncer) zapcore.Core {
+		if queue != nil {
+			return queue.core(newEncoder(config), ws, level)
+		}
+		return zapcore.NewCore(newEncoder(config), ws, level)
 	}
 
-	return
-}
-
-func newZLogWithFile(config *ZLogConfig, options ...zap.Option) (logger *zap.Logger) {
-	core := newFileCore(config, options...)
-	logger = zap.New(core, zap.AddCaller(), zap.AddStacktrace(config.Level.toZapLevel()))
-	return
-}
-
-func newFileCore(config *ZLogConfig, options ...zap.Option) zapcore.Core {
-	dir := filepath.Dir(config.LogFile)
-	if err := fileutil.MkdirIfNecessary(dir); err != nil {
-		log.Panicf("创建日志目录[%s]失败：%+v\n", dir, errors.WithStack(err))
+	cores := make([]zapcore.Core, 0, 2)
+	file, console := parseLogMode(config.LogMode)
+	if console {
+		cores = append(cores, newCore(zapcore.Lock(zapcore.AddSync(consoleWriter{os.Stdout}))))
 	}
-	if config.MaxAge <= 0 {
-		config.MaxAge = logMaxAge
+	if file {
+		if config.MaxAge <= 0 {
+			config.MaxAge = logMaxAge
+		}
+		if config.MaxSize <= 0 {
+			config.MaxSize = logMaxSize
+		}
+		if config.MaxBackups <= 0 {
+			config.MaxBackups = logMaxBackups
+		}
+		if writer, err = newFileWriter(config); err != nil {
+			if queue != nil {
+				queue.Close()
+			}
+			return nil, nil, nil, err
+		}
+		cores = append(cores, newCore(writer))
 	}
-	if config.MaxSize <= 0 {
-		config.MaxSize = logMaxSize
+
+	core := zapcore.NewTee(cores...)
+	if sampler != nil {
+		// 在异步队列之前采样，被丢弃的日志不占用队列
+		core = sampler.wrap(core)
 	}
-	if config.MaxBackups <= 0 {
-		config.MaxBackups = logMaxBackups
+	stacktraceLevel := zap.ErrorLevel
+	if config.StacktraceLevel != "" {
+		stacktraceLevel = config.StacktraceLevel.ZapLevel()
 	}
-	// filename := filepath.Base(config.LogFile)
-	hook := lumberjack.Logger{
-		Filename:   config.LogFile,
-		MaxSize:    config.MaxSize,
-		MaxAge:     config.MaxAge,
-		MaxBackups: config.MaxBackups,
-		Compress:   config.Compress,
-		LocalTime:  true,
+	zapOptions := []zap.Option{
+		zap.ErrorOutput(zapcore.Lock(os.Stderr)),
+		zap.AddCaller(),
+		zap.AddStacktrace(stacktraceLevel),
 	}
-	var encoder zapcore.Encoder
-	if config.Encoding == logEncodingJson {
-		encoder = zapcore.NewJSONEncoder(newEncoderConfig())
-	} else {
-		encoder = zapcore.NewConsoleEncoder(newEncoderConfig())
+	if !config.Development && isDevelopment(options...) {
+		// options中传入zap.Development()时zLog的DPanic同样panic
+		config.Development = true
 	}
-	core := zapcore.NewCore(encoder, zapcore.AddSync(&hook), config.Level.toZapLevel())
-	return core
+	if config.Development {
+		zapOptions = append(zapOptions, zap.Development())
+	}
+	zapOptions = append(zapOptions, options...)
+	logger = zap.New(core, zapOptions...)
+	return logger, writer, queue, nil
 }
 
-func newZLogWithFileAndConsole(config *ZLogConfig, options ...zap.Option) (logger *zap.Logger) {
-	consoleCore := newZLogWithConsole(config, options...)
-	fileCore := newFileCore(config, options...)
-
-	core := zapcore.NewTee(consoleCore.Core(), fileCore)
-	logger = zap.New(core, zap.AddCaller(), zap.AddStacktrace(config.Level.toZapLevel()))
-
-	return
+func newEncoder(config *ZLogConfig) zapcore.Encoder {
+	if config.Encoding == logEncodingJson {
+		return newStackEncoder(zapcore.NewJSONEncoder(newEncoderConfig(config)), config)
+	}
+	return newStackEncoder(zapcore.NewConsoleEncoder(newEncoderConfig(config)), config)
 }
 
 // =========================================================== 接口方法 ===========================================================
 
 func (z *zLog) Debug(msg string, fields ...zapcore.Field) {
-	z.withName(func(logger *zap.Logger) {
-		logger.Debug(z.withPrefix(msg), fields...)
-	})
+	z.log(zapcore.DebugLevel, msg, fields)
 }
 
 func (z *zLog) Debugf(template string, args ...interface{}) {
-	z.withName(func(logger *zap.Logger) {
-		logger.Sugar().Debugf(z.withPrefix(template), args...)
-	})
+	z.logf(zapcore.DebugLevel, template, args)
 }
 
 func (z *zLog) Info(msg string, fields ...zapcore.Field) {
-	z.withName(func(logger *zap.Logger) {
-		logger.Info(z.withPrefix(msg), fields...)
-	})
+	z.log(zapcore.InfoLevel, msg, fields)
 }
 
 func (z *zLog) Infof(template string, args ...interface{}) {
-	z.withName(func(logger *zap.Logger) {
-		logger.Sugar().Infof(z.withPrefix(template), args...)
-	})
+	z.logf(zapcore.InfoLevel, template, args)
 }
 
 func (z *zLog) Warn(msg string, fields ...zapcore.Field) {
-	z.withName(func(logger *zap.Logger) {
-		logger.Warn(z.withPrefix(msg), fields...)
-	})
+	z.log(zapcore.WarnLevel, msg, fields)
 }
 
 func (z *zLog) Warnf(template string, args ...interface{}) {
-	z.withName(func(logger *zap.Logger) {
-		logger.Sugar().Warnf(z.withPrefix(template), args...)
-	})
+	z.logf(zapcore.WarnLevel, template, args)
 }
 
 func (z *zLog) Error(msg string, fields ...zapcore.Field) {
-	z.withName(func(logger *zap.Logger) {
-		logger.Error(z.withPrefix(msg), fields...)
-	})
+	z.log(zapcore.ErrorLevel, msg, fields)
 }
 
 func (z *zLog) Errorf(template string, args ...interface{}) {
-	z.withName(func(logger *zap.Logger) {
-		logger.Sugar().Errorf(z.withPrefix(template), args...)
-	})
+	z.logf(zapcore.ErrorLevel, template, args)
+}
+
+func (z *zLog) DPanic(msg string, fields ...zapcore.Field) {
+	z.log(zapcore.DPanicLevel, msg, fields)
+}
+
+func (z *zLog) DPanicf(template string, args ...interface{}) {
+	z.logf(zapcore.DPanicLevel, template, args)
+}
+
+func (z *zLog) Panic(msg string, fields ...zapcore.Field) {
+	z.log(zapcore.PanicLevel, msg, fields)
+}
+
+func (z *zLog) Panicf(template string, args ...interface{}) {
+	z.logf(zapcore.PanicLevel, template, args)
 }
 
 func (z *zLog) Fatal(msg string, fields ...zapcore.Field) {
-	z.withName(func(logger *zap.Logger) {
-		logger.Fatal(z.withPrefix(msg), fields...)
-	})
+	z.log(zapcore.FatalLevel, msg, fields)
 }
 
 func (z *zLog) Fatalf(template string, args ...interface{}) {
-	z.withName(func(logger *zap.Logger) {
-		logger.Sugar().Fatalf(z.withPrefix(template), args...)
-	})
+	z.logf(zapcore.FatalLevel, template, args)
+}
+
+// =========================================================== 键值对接口方法 ===========================================================
+
+func (z *zLog) Debugw(msg string, keysAndValues ...interface{}) {
+	z.logw(zapcore.DebugLevel, msg, keysAndValues)
+}
+
+func (z *zLog) Infow(msg string, keysAndValues ...interface{}) {
+	z.logw(zapcore.InfoLevel, msg, keysAndValues)
+}
+
+func (z *zLog) Warnw(msg string, keysAndValues ...interface{}) {
+	z.logw(zapcore.WarnLevel, msg, keysAndValues)
+}
+
+func (z *zLog) Errorw(msg string, keysAndValues ...interface{}) {
+	z.logw(zapcore.ErrorLevel, msg, keysAndValues)
+}
+
+func (z *zLog) DPanicw(msg string, keysAndValues ...interface{}) {
+	z.logw(zapcore.DPanicLevel, msg, keysAndValues)
+}
+
+func (z *zLog) Panicw(msg string, keysAndValues ...interface{}) {
+	z.logw(zapcore.PanicLevel, msg, keysAndValues)
+}
+
+func (z *zLog) Fatalw(msg string, keysAndValues ...interface{}) {
+	z.logw(zapcore.FatalLevel, msg, keysAndValues)
 }
 
 // =========================================================== 带链路追踪的接口方法 ===========================================================
 
 func (z *zLog) DebugWithTrace(ctx context.Context, msg string, fields ...zapcore.Field) {
-	msg = z.withPrefix(msg)
-	opt := WithTrace(ctx)
-	if nz, err := opt(z); err == nil {
-		nz.withName(func(logger *zap.Logger) {
-			logger.Debug(msg, fields...)
-		})
-		return
-	}
-	z.withName(func(logger *zap.Logger) {
-		logger.Debug(msg, fields...)
-	})
+	z.withContext(ctx).log(zapcore.DebugLevel, msg, fields)
 }
 
 func (z *zLog) DebugfWithTrace(ctx context.Context, template string, args ...interface{}) {
-	template = z.withPrefix(template)
-	opt := WithTrace(ctx)
-	if nz, err := opt(z); err == nil {
-		nz.withName(func(logger *zap.Logger) {
-			logger.Sugar().Debugf(template, args...)
-		})
-		return
-	}
-	z.withName(func(logger *zap.Logger) {
-		logger.Sugar().Debugf(template, args...)
-	})
+	z.withContext(ctx).logf(zapcore.DebugLevel, template, args)
 }
 
 func (z *zLog) InfoWithTrace(ctx context.Context, msg string, fields ...zapcore.Field) {
-	msg = z.withPrefix(msg)
-	opt := WithTrace(ctx)
-	if nz, err := opt(z); err == nil {
-		nz.withName(func(logger *zap.Logger) {
-			logger.Info(msg, fields...)
-		})
-		return
-	}
-	z.withName(func(logger *zap.Logger) {
-		logger.Info(msg, fields...)
-	})
+	z.withContext(ctx).log(zapcore.InfoLevel, msg, fields)
 }
 
 func (z *zLog) InfofWithTrace(ctx context.Context, template string, args ...interface{}) {
-	template = z.withPrefix(template)
-	opt := WithTrace(ctx)
-	if nz, err := opt(z); err == nil {
-		nz.withName(func(logger *zap.Logger) {
-			logger.Sugar().Infof(template, args...)
-		})
-		return
-	}
-	z.withName(func(logger *zap.Logger) {
-		logger.Sugar().Infof(template, args...)
-	})
+	z.withContext(ctx).logf(zapcore.InfoLevel, template, args)
 }
 
 func (z *zLog) WarnWithTrace(ctx context.Context, msg string, fields ...zapcore.Field) {
-	msg = z.withPrefix(msg)
-	opt := WithTrace(ctx)
-	if nz, err := opt(z); err == nil {
-		nz.withName(func(logger *zap.Logger) {
-			logger.Warn(msg, fields...)
-		})
-		return
-	}
-	z.withName(func(logger *zap.Logger) {
-		logger.Warn(msg, fields...)
-	})
+	z.withContext(ctx).log(zapcore.WarnLevel, msg, fields)
 }
 
 func (z *zLog) WarnfWithTrace(ctx context.Context, template string, args ...interface{}) {
-	template = z.withPrefix(template)
-	opt := WithTrace(ctx)
-	if nz, err := opt(z); err == nil {
-		nz.withName(func(logger *zap.Logger) {
-			logger.Sugar().Warnf(template, args...)
-		})
-		return
-	}
-	z.withName(func(logger *zap.Logger) {
-		logger.Sugar().Warnf(template, args...)
-	})
+	z.withContext(ctx).logf(zapcore.WarnLevel, template, args)
 }
 
 func (z *zLog) ErrorWithTrace(ctx context.Context, msg string, fields ...zapcore.Field) {
-	msg = z.withPrefix(msg)
-	opt := WithTrace(ctx)
-	if nz, err := opt(z); err == nil {
-		nz.withName(func(logger *zap.Logger) {
-			logger.Error(msg, fields...)
-		})
-		return
-	}
-	z.withName(func(logger *zap.Logger) {
-		logger.Error(msg, fields...)
-	})
+	z.withContext(ctx).log(zapcore.ErrorLevel, msg, fields)
 }
 
 func (z *zLog) ErrorfWithTrace(ctx context.Context, template string, args ...interface{}) {
-	template = z.withPrefix(template)
-	opt := WithTrace(ctx)
-	if nz, err := opt(z); err == nil {
-		nz.withName(func(logger *zap.Logger) {
-			logger.Sugar().Errorf(template, args...)
-		})
-		return
-	}
-	z.withName(func(logger *zap.Logger) {
-		logger.Sugar().Errorf(template, args...)
-	})
+	z.withContext(ctx).logf(zapcore.ErrorLevel, template, args)
+}
+
+func (z *zLog) DPanicWithTrace(ctx context.Context, msg string, fields ...zapcore.Field) {
+	z.withContext(ctx).log(zapcore.DPanicLevel, msg, fields)
+}
+
+func (z *zLog) DPanicfWithTrace(ctx context.Context, template string, args ...interface{}) {
+	z.withContext(ctx).logf(zapcore.DPanicLevel, template, args)
+}
+
+func (z *zLog) PanicWithTrace(ctx context.Context, msg string, fields ...zapcore.Field) {
+	z.withContext(ctx).log(zapcore.PanicLevel, msg, fields)
+}
+
+func (z *zLog) PanicfWithTrace(ctx context.Context, template string, args ...interface{}) {
+	z.withContext(ctx).logf(zapcore.PanicLevel, template, args)
 }
 
 func (z *zLog) FatalWithTrace(ctx context.Context, msg string, fields ...zapcore.Field) {
-	msg = z.withPrefix(msg)
-	opt := WithTrace(ctx)
-	if nz, err := opt(z); err == nil {
-		nz.withName(func(logger *zap.Logger) {
-			logger.Fatal(msg, fields...)
-		})
-		return
-	}
-	z.withName(func(logger *zap.Logger) {
-		logger.Fatal(msg, fields...)
-	})
+	z.withContext(ctx).log(zapcore.FatalLevel, msg, fields)
 }
 
 func (z *zLog) FatalfWithTrace(ctx context.Context, template string, args ...interface{}) {
-	template = z.withPrefix(template)
-	opt := WithTrace(ctx)
-	if nz, err := opt(z); err == nil {
-		nz.withName(func(logger *zap.Logger) {
-			logger.Sugar().Fatalf(template, args...)
-		})
-		return
-	}
-	z.withName(func(logger *zap.Logger) {
-		logger.Sugar().Fatalf(template, args...)
-	})
+	z.withContext(ctx).logf(zapcore.FatalLevel, template, args)
 }
 
-func (z *zLog) GetZCore(name string) *zap.Logger {
-	return z.loggers[name]
+func (z *zLog) DebugwWithTrace(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	z.withContext(ctx).logw(zapcore.DebugLevel, msg, keysAndValues)
 }
 
-// =========================================================== 带前缀打印的接口方法 ===========================================================
+func (z *zLog) InfowWithTrace(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	z.withContext(ctx).logw(zapcore.InfoLevel, msg, keysAndValues)
+}
+
+func (z *zLog) WarnwWithTrace(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	z.withContext(ctx).logw(zapcore.WarnLevel, msg, keysAndValues)
+}
+
+func (z *zLog) ErrorwWithTrace(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	z.withContext(ctx).logw(zapcore.ErrorLevel, msg, keysAndValues)
+}
+
+func (z *zLog) DPanicwWithTrace(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	z.withContext(ctx).logw(zapcore.DPanicLevel, msg, keysAndValues)
+}
+
+func (z *zLog) PanicwWithTrace(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	z.withContext(ctx).logw(zapcore.PanicLevel, msg, keysAndValues)
+}
+
+func (z *zLog) FatalwWithTrace(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	z.withContext(ctx).logw(zapcore.FatalLevel, msg, keysAndValues)
+}
+
+// 修改指定日志记录器的级别，立即对所有派生日志生效
+func (z *zLog) SetLevel(name string, level LogLevel) error {
+	if !level.valid() {
+		return &ConfigError{Index: -1, Name: name, Field: "level", Value: level.String(), Err: ErrInvalidLevel}
+	}
 
-func (z *zLog) WithPrefix(prefix string) IZLog {
 	z.lock.Lock()
 	defer z.lock.Unlock()
 
-	cfgs := make([]*ZLogConfig, 0)
-	for _, cfg := range z.cfgs {
-		cfgs = append(cfgs, cfg)
+	atomicLevel, ok := z.state.Load().levels[name]
+	if !ok {
+		return errors.WithMessagef(ErrUnknownLogger, "%s", name)
 	}
+	z.cancelLevelOverride(name)
+	atomicLevel.SetLevel(level.ZapLevel())
+	return nil
+}
+
+// 获取指定日志记录器的当前级别
+func (z *zLog) GetLevel(name string) (LogLevel, error) {
+	atomicLevel, ok := z.state.Load().levels[name]
+	if !ok {
+		return "", errors.WithMessagef(ErrUnknownLogger, "%s", name)
+	}
+	return FromZapLevel(atomicLevel.Level()), nil
+}
+
+// 获取各日志记录器的统计信息
+func (z *zLog) Stats() map[string]LoggerStats {
+	state := z.state.Load()
+	stats := make(map[string]LoggerStats, len(state.cfgs))
+	for name := range state.cfgs {
+		var s LoggerStats
+		if queue, ok := state.queues[name]; ok {
+			s.Dropped = queue.Dropped()
+		}
+		if sampler, ok := state.samplers[name]; ok {
+			s.Sampled = sampler.Sampled()
+		}
+		stats[name] = s
+	}
+	return stats
+}
+
+func (z *zLog) GetZCore(name string) *zap.Logger {
+	return z.state.Load().loggers[name]
+}
+
+// =========================================================== 生命周期 ===========================================================
+
+// 刷新所有日志记录器的缓冲区（包括异步队列）
+func (z *zLog) Sync() error {
+	var errs error
+	for _, logger := range z.state.Load().loggers {
+		errs = multierr.Append(errs, logger.Sync())
+	}
+	return errs
+}
+
+// 刷新并关闭所有日志记录器，之后的日志调用不再输出
+func (z *zLog) Close() error {
+	if !z.closed.CompareAndSwap(false, true) {
+		return nil
+	}
+
+	z.lock.Lock()
+	for name := range z.overrides {
+		z.cancelLevelOverride(name)
+	}
+	z.lock.Unlock()
+
+	return z.state.Load().release()
+}
 
-	newZlog := newZLog(cfgs, z.options...)
-	newZlog.prefix = prefix
-	return newZlog
+// 在ctx结束前关闭日志，超时返回ctx.Err()，关闭操作会在后台继续完成
+func (z *zLog) Shutdown(ctx context.Context) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- z.Close()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// =========================================================== 带前缀打印的接口方法 ===========================================================
+
+func (z *zLog) WithPrefix(prefix string) IZLog {
+	nz := *z
+	nz.prefix = prefix
+	return &nz
 }
 
 // 使用指定日志记录器
 func (z *zLog) WithName(names ...string) IZLog {
-	z.lock.Lock()
-	defer z.lock.Unlock()
+	nz := *z
+	nz.names = append([]string(nil), names...)
+	return &nz
+}
 
-	cfgs := make([]*ZLogConfig, 0)
-	for _, cfg := range z.cfgs {
-		cfgs = append(cfgs, cfg)
+// 附加字段，对所有选中的日志记录器生效
+func (z *zLog) With(fields ...zapcore.Field) IZLog {
+	if len(fields) == 0 {
+		return z
 	}
+	return z.withFields(fields...)
+}
 
-	newZlog := newZLog(cfgs, z.options...)
-	usedLoggers := make(map[string]*zap.Logger, 0)
-	for _, name := range names {
-		if logger, ok := z.loggers[name]; ok {
-			usedLoggers[name] = logger
+// 依次应用选项，失败的选项会被跳过并记录一条错误日志
+func (z *zLog) WithOptions(opts ...Option) IZLog {
+	nz := z
+	for _, opt := range opts {
+		next, err := opt(nz)
+		if err != nil {
+			nz.Errorf("应用日志选项失败：%v", err)
+			continue
 		}
+		nz = next
 	}
-	newZlog.usedLoggers = usedLoggers
-
-	return newZlog
+	return nz
 }
 
 // =========================================================== 私有方法 ===========================================================
 
-func (z *zLog) withName(f func(logger *zap.Logger)) {
+// 派生附加了字段的日志，不修改z.fields的底层数组
+func (z *zLog) withFields(fields ...zapcore.Field) *zLog {
+	nz := *z
+	nz.fields = append(z.fields[:len(z.fields):len(z.fields)], fields...)
+	return &nz
+}
+
+func (z *zLog) log(lvl zapcore.Level, msg string, fields []zapcore.Field) {
+	msg = z.withPrefix(msg)
+	if len(z.fields) > 0 {
+		fields = append(z.fields[:len(z.fields):len(z.fields)], fields...)
+	}
+	development := z.withName(func(logger *zap.Logger, _ *zap.SugaredLogger) {
+		if ce := logger.Check(lvl, msg); ce != nil {
+			ce.Write(fields...)
+		}
+	})
+	z.terminate(lvl, msg, development)
+}
+
+// 只在有日志记录器启用lvl时格式化消息，与zap.SugaredLogger的格式化规则一致
+func (z *zLog) logf(lvl zapcore.Level, template string, args []interface{}) {
+	var msg string
+	formatted := false
+	development := z.withName(func(logger *zap.Logger, _ *zap.SugaredLogger) {
+		if lvl < zapcore.DPanicLevel && !logger.Core().Enabled(lvl) {
+			return
+		}
+		if !formatted {
+			msg = z.withPrefix(sprintf(template, args))
+			formatted = true
+		}
+		if ce := logger.Check(lvl, msg); ce != nil {
+			ce.Write(z.fields...)
+		}
+	})
+	if !formatted {
+		msg = z.withPrefix(sprintf(template, args))
+	}
+	z.terminate(lvl, msg, development)
+}
+
+// 使用键值对附加字段，与zap.SugaredLogger的*w方法一致
+func (z *zLog) logw(lvl zapcore.Level, msg string, keysAndValues []interface{}) {
+	msg = z.withPrefix(msg)
+	if len(z.fields) > 0 {
+		args := make([]interface{}, 0, len(z.fields)+len(keysAndValues))
+		for _, field := range z.fields {
+			args = append(args, field)
+		}
+		keysAndValues = append(args, keysAndValues...)
+	}
+	development := z.withName(func(_ *zap.Logger, sugar *zap.SugaredLogger) {
+		sugar.Logw(lvl, msg, keysAndValues...)
+	})
+	z.terminate(lvl, msg, development)
+}
+
+// 写入后不执行任何操作的CheckWriteHook
+type noopHook struct{}
+
+func (noopHook) OnWrite(*zapcore.CheckedEntry, []zapcore.Field) {}
+
+// options是否开启开发模式：开发模式下即使core不写入DPanic日志，Check也会返回带panic钩子的CheckedEntry
+func isDevelopment(options ...zap.Option) bool {
+	return zap.New(zapcore.NewNopCore(), options...).Check(zapcore.DPanicLevel, "") != nil
+}
+
+// 写入所有选中的日志记录器后再panic或执行FatalHook，避免只写入第一个日志记录器
+func (z *zLog) terminate(lvl zapcore.Level, msg string, development bool) {
+	switch {
+	case lvl == zapcore.FatalLevel:
+		z.fatal(msg)
+	case lvl == zapcore.PanicLevel, lvl == zapcore.DPanicLevel && development:
+		panic(msg)
+	}
+}
+
+// 依次使用选中的日志记录器，不加锁，与重新加载并发时使用替换前或替换后的记录器，返回是否包含开发模式的日志记录器
+func (z *zLog) withName(f func(logger *zap.Logger, sugar *zap.SugaredLogger)) (development bool) {
+	if z.closed.Load() {
+		return false
+	}
+
+	state := z.state.Load()
+	names := z.names
+	if len(names) == 0 {
+		names = state.defaults
+	}
+	for _, name := range names {
+		if logger, ok := state.wrapped[name]; ok {
+			f(logger, state.sugars[name])
+			development = development || state.cfgs[name].Development
+		}
+	}
+	return development
+}
+
+// 替换为新日志的记录器，所有派生日志随之生效，返回包含被替换的记录器、写入器和队列的状态
+func (z *zLog) swap(nz *zLog) *zLogState {
 	z.lock.Lock()
 	defer z.lock.Unlock()
 
-	for _, logger := range z.usedLoggers {
-		f(logger)
+	if z.closed.Load() {
+		// 已关闭的日志不再接收新的记录器，由调用方释放nz
+		return nz.state.Load()
 	}
+	state := nz.state.Load()
+	state.fatalHook.root.Store(z.zLogRoot)
+	return z.state.Swap(state)
+}
 
-	z.resetUsedLogger()
+// 同步所有记录器，关闭异步队列和文件写入器，仍在使用旧状态的日志会在调用方协程中同步写入
+func (s *zLogState) release() error {
+	var errs error
+	for _, logger := range s.loggers {
+		errs = multierr.Append(errs, logger.Sync())
+	}
+	for _, queue := range s.queues {
+		errs = multierr.Append(errs, queue.Close())
+	}
+	for _, writer := range s.writers {
+		errs = multierr.Append(errs, writer.release())
+	}
+	return errs
 }
 
-func (z *zLog) resetUsedLogger() {
-	z.usedLoggers = make(map[string]*zap.Logger, 0)
-	for name, config := range z.cfgs {
-		if config.Default {
-			z.usedLoggers[name] = z.loggers[name]
-		}
+// 控制台输出不需要fsync，避免Sync时返回"sync /dev/stdout: invalid argument"
+type consoleWriter struct {
+	io.Writer
+}
+
+func closeWriters(writers []*fileWriter) error {
+	var errs error
+	for _, writer := range writers {
+		errs = multierr.Append(errs, writer.Close())
 	}
+	return errs
 }
 
 func (z *zLog) withPrefix(original string) string {
@@ -504,15 +871,36 @@ func (z *zLog) withPrefix(original string) string {
 	return original
 }
 
-func newEncoderConfig() zapcore.EncoderConfig {
+func sprintf(template string, args []interface{}) string {
+	if len(args) == 0 {
+		return template
+	}
+	if template == "" {
+		return fmt.Sprint(args...)
+	}
+	return fmt.Sprintf(template, args...)
+}
+
+func newEncoderConfig(config *ZLogConfig) zapcore.EncoderConfig {
 	encoderConfig := zap.NewProductionEncoderConfig()
 	encoderConfig.EncodeTime = func(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
 		enc.AppendString(t.Format("2006-01-02 15:04:05.000"))
 	}
 	encoderConfig.StacktraceKey = "stacktrace"
 	encoderConfig.CallerKey = "caller"
-	encoderConfig.EncodeCaller = func(ec zapcore.EntryCaller, pae zapcore.PrimitiveArrayEncoder) {
+	encoderConfig.EncodeCaller = newCallerEncoder(config.CallerEncoding)
+	return encoderConfig
+}
 
+func newCallerEncoder(encoding string) zapcore.CallerEncoder {
+	switch encoding {
+	case callerEncodingFull:
+		return zapcore.FullCallerEncoder
+	case callerEncodingFunction:
+		return func(ec zapcore.EntryCaller, pae zapcore.PrimitiveArrayEncoder) {
+			pae.AppendString(ec.Function)
+		}
+	default:
+		return zapcore.ShortCallerEncoder
 	}
-	return encoderConfig
 }
@@ -4,7 +4,7 @@
 // # Created Date: 2024/10/08 18:04:40                                         #
 // # Author: realjf                                                            #
 // # -----                                                                     #
-// # Last Modified: 2025/06/05 07:47:37                                        #
+// # Last Modified: 2026/10/17 20:55:17                                        #
 // # Modified By: realjf                                                       #
 // # -----                                                                     #
 // #                                                                           #
@@ -13,7 +13,16 @@ package zlog_test
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
 
 	"github.com/realjf/zlog"
 	"github.com/realjf/zlog/trace"
@@ -111,3 +120,368 @@ func TestWithPrefix2(t *testing.T) {
 	go zlog.ZLog().WithPrefix("[test2]").Infof("hello %s", "realjf2")
 	zlog.ZLog().WithPrefix("[test3]").Infof("hello %s", "realjf3")
 }
+
+func TestSetLevel(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "zlog.log")
+	z := zlog.NewZLog([]*zlog.ZLogConfig{
+		{
+			Level:    "info",
+			LogMode:  "file",
+			Encoding: "json",
+			LogFile:  logFile,
+			Name:     "zlog",
+		},
+	})
+	prefixed := z.WithPrefix("[test]")
+
+	z.Debug("debug before")
+	if err := z.SetLevel("zlog", "debug"); err != nil {
+		t.Fatal(err)
+	}
+	if level, err := z.GetLevel("zlog"); err != nil || level != "debug" {
+		t.Fatalf("unexpected level %s, %v", level, err)
+	}
+	z.Debug("debug after")
+	prefixed.Debug("debug prefixed")
+
+	if err := z.SetLevel("zlog2", "debug"); !errors.Is(err, zlog.ErrUnknownLogger) {
+		t.Fatalf("expected ErrUnknownLogger, got %v", err)
+	}
+	if err := z.SetLevel("zlog", "verbose"); !errors.Is(err, zlog.ErrInvalidLevel) {
+		t.Fatalf("expected ErrInvalidLevel, got %v", err)
+	}
+
+	data, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(data)
+	if strings.Contains(content, "debug before") {
+		t.Errorf("unexpected debug line in %s", content)
+	}
+	for _, msg := range []string{"debug after", "[test] debug prefixed"} {
+		if !strings.Contains(content, msg) {
+			t.Errorf("missing %q in %s", msg, content)
+		}
+	}
+}
+
+func TestClose(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "zlog.log")
+	z := zlog.NewZLog([]*zlog.ZLogConfig{
+		{
+			LogMode:  "file|console",
+			Encoding: "json",
+			LogFile:  logFile,
+			Name:     "zlog",
+			Async:    &zlog.AsyncConfig{QueueSize: 16},
+		},
+	})
+	ctx := trace.WithTraceContext(context.Background(), trace.NewTraceContext())
+	pz := z.WithPrefix("[close] ")
+
+	for i := 0; i < 100; i++ {
+		z.Infof("before close %d", i)
+	}
+	if err := z.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if err := z.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if n := countLines(t, logFile, "before close"); n != 100 {
+		t.Fatalf("expected 100 lines, got %d", n)
+	}
+
+	// 关闭后的调用不输出且不会panic
+	z.Info("after close")
+	z.InfoWithTrace(ctx, "after close")
+	pz.Info("after close")
+	z.WithName("zlog").Error("after close")
+	if err := z.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if n := countLines(t, logFile, "after close"); n != 0 {
+		t.Fatalf("expected no lines after close, got %d", n)
+	}
+}
+
+func TestShutdown(t *testing.T) {
+	z := zlog.NewZLog([]*zlog.ZLogConfig{
+		{
+			LogMode:  "file",
+			Encoding: "json",
+			LogFile:  filepath.Join(t.TempDir(), "zlog.log"),
+			Name:     "zlog",
+		},
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := z.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	if err := z.Shutdown(ctx); err != nil && !errors.Is(err, context.Canceled) {
+		t.Fatal(err)
+	}
+}
+
+func newDeriveZLog(tb testing.TB) (zlog.IZLog, string) {
+	dir := tb.TempDir()
+	return zlog.NewZLog([]*zlog.ZLogConfig{
+		{
+			LogMode:  "file",
+			Encoding: "json",
+			LogFile:  filepath.Join(dir, "zlog.log"),
+			Name:     "zlog",
+			Default:  true,
+		},
+		{
+			LogMode:  "file",
+			Encoding: "json",
+			LogFile:  filepath.Join(dir, "zlog2.log"),
+			Name:     "zlog2",
+		},
+	}), dir
+}
+
+func TestDerive(t *testing.T) {
+	z, dir := newDeriveZLog(t)
+	defer z.Close()
+
+	// 派生日志与父日志共享记录器，不会重新创建写入器
+	if n := testing.AllocsPerRun(100, func() { z.WithPrefix("[test]") }); n > 1 {
+		t.Fatalf("WithPrefix allocs = %v, want <= 1", n)
+	}
+	if n := testing.AllocsPerRun(100, func() { z.WithName("zlog", "zlog2") }); n > 3 {
+		t.Fatalf("WithName allocs = %v, want <= 3", n)
+	}
+
+	// WithName选择的日志记录器对派生日志的每次调用都生效
+	child := z.WithName("zlog2").WithPrefix("[child]")
+	child.Info("child")
+	child.Infof("child %d", 2)
+	ctx := trace.WithTraceContext(context.Background(), trace.NewTraceContext())
+	child.InfoWithTrace(ctx, "child")
+	z.Info("parent")
+	if err := z.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := countLines(t, filepath.Join(dir, "zlog2.log"), "[child] child"); n != 3 {
+		t.Fatalf("expected 3 child lines, got %d", n)
+	}
+	if n := countLines(t, filepath.Join(dir, "zlog2.log"), "traceID"); n != 1 {
+		t.Fatalf("expected 1 trace line, got %d", n)
+	}
+	if n := countLines(t, filepath.Join(dir, "zlog.log"), "child"); n != 0 {
+		t.Fatalf("expected no child lines in default logger, got %d", n)
+	}
+	if n := countLines(t, filepath.Join(dir, "zlog.log"), "parent"); n != 1 {
+		t.Fatalf("expected 1 parent line, got %d", n)
+	}
+}
+
+func TestWith(t *testing.T) {
+	z, dir := newDeriveZLog(t)
+	defer z.Close()
+	ctx := trace.WithTraceContext(context.Background(), trace.NewTraceContext())
+
+	child := z.With(zap.String("user_id", "u1")).
+		WithName("zlog", "zlog2").
+		WithPrefix("[child]").
+		WithOptions(zlog.WithTrace(ctx), zlog.WithFields(zap.Int("job_id", 7)))
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			child.Infof("with %s", "fields")
+		}()
+	}
+	wg.Wait()
+	z.Info("parent")
+	if err := z.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"zlog.log", "zlog2.log"} {
+		content := readLogFile(t, filepath.Join(dir, name))
+		for _, s := range []string{`"user_id":"u1"`, `"job_id":7`, `"traceID"`, "[child] with fields"} {
+			if n := strings.Count(content, s); n != 4 {
+				t.Errorf("expected 4 %s in %s, got %d", s, name, n)
+			}
+		}
+	}
+	if n := countLines(t, filepath.Join(dir, "zlog.log"), "user_id"); n != 4 {
+		t.Errorf("fields leaked into parent logger")
+	}
+}
+
+func TestInfow(t *testing.T) {
+	z, dir := newDeriveZLog(t)
+	defer z.Close()
+	ctx := trace.WithTraceContext(context.Background(), trace.NewTraceContext())
+
+	child := z.With(zap.String("user_id", "u1")).WithPrefix("[child]")
+	child.Infow("created", "order_id", 42, zap.Bool("paid", true))
+	child.InfowWithTrace(ctx, "traced", "order_id", 43)
+	child.Debugw("debug", "order_id", 44)
+	z.WithName("zlog2").Errorw("failed", "order_id", 45)
+	if err := z.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	content := readLogFile(t, filepath.Join(dir, "zlog.log"))
+	for _, s := range []string{
+		`"msg":"[child] created","user_id":"u1","order_id":42,"paid":true`,
+		`"msg":"[child] traced","user_id":"u1","traceID"`,
+		`"msg":"[child] debug","user_id":"u1","order_id":44`,
+	} {
+		if !strings.Contains(content, s) {
+			t.Errorf("missing %s in %s", s, content)
+		}
+	}
+	if !strings.Contains(readLogFile(t, filepath.Join(dir, "zlog2.log")), `"msg":"failed","order_id":45`) {
+		t.Errorf("missing errorw line in zlog2.log")
+	}
+}
+
+func expectPanic(t *testing.T, want bool, f func()) {
+	t.Helper()
+	defer func() {
+		if got := recover() != nil; got != want {
+			t.Fatalf("panic = %v, want %v", got, want)
+		}
+	}()
+	f()
+}
+
+func TestPanic(t *testing.T) {
+	z, dir := newDeriveZLog(t)
+	defer z.Close()
+	both := z.WithName("zlog", "zlog2")
+
+	// 写入所有选中的日志记录器后才panic
+	expectPanic(t, true, func() { both.Panic("panic msg") })
+	expectPanic(t, true, func() { both.Panicf("panicf %d", 1) })
+	expectPanic(t, true, func() { both.Panicw("panicw", "k", 1) })
+	expectPanic(t, false, func() { both.DPanic("dpanic msg") })
+	// GetZCore返回的记录器保持zap原有的行为
+	expectPanic(t, true, func() { z.GetZCore("zlog").Panic("raw panic") })
+	expectPanic(t, false, func() { z.GetZCore("zlog").DPanic("raw dpanic") })
+	if err := z.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"zlog.log", "zlog2.log"} {
+		content := readLogFile(t, filepath.Join(dir, name))
+		for _, s := range []string{`"level":"panic","ts"`, `"msg":"panicf 1"`, `"msg":"panicw","k":1`, `"level":"dpanic"`} {
+			if !strings.Contains(content, s) {
+				t.Errorf("missing %s in %s", s, name)
+			}
+		}
+	}
+
+	dev := zlog.NewZLog([]*zlog.ZLogConfig{
+		{
+			LogMode:     "file",
+			LogFile:     filepath.Join(t.TempDir(), "zlog.log"),
+			Development: true,
+		},
+	})
+	defer dev.Close()
+	expectPanic(t, true, func() { dev.DPanicf("dpanic %s", "development") })
+	expectPanic(t, true, func() { dev.GetZCore("").DPanic("raw dpanic") })
+
+	// 通过options传入zap.Development()与Development: true一致
+	devOption := zlog.NewZLog([]*zlog.ZLogConfig{
+		{
+			LogMode: "file",
+			LogFile: filepath.Join(t.TempDir(), "zlog.log"),
+		},
+	}, zap.Development())
+	defer devOption.Close()
+	expectPanic(t, true, func() { devOption.DPanic("dpanic option") })
+	expectPanic(t, true, func() { devOption.GetZCore("").DPanic("raw dpanic option") })
+}
+
+// 使用go test -race运行
+func TestConcurrentLogging(t *testing.T) {
+	z, dir := newDeriveZLog(t)
+	defer z.Close()
+	ctx := trace.WithTraceContext(context.Background(), trace.NewTraceContext())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				switch j % 4 {
+				case 0:
+					z.Info("concurrent")
+				case 1:
+					z.WithPrefix(fmt.Sprintf("[%d]", i)).Infof("concurrent %d", j)
+				case 2:
+					z.WithName("zlog", "zlog2").Info("concurrent")
+				case 3:
+					z.WithName("zlog2").InfoWithTrace(ctx, "concurrent")
+				}
+			}
+		}(i)
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for j := 0; j < 100; j++ {
+			_ = z.SetLevel("zlog2", "info")
+			_, _ = z.GetLevel("zlog")
+			_ = z.Stats()
+		}
+	}()
+	wg.Wait()
+	if err := z.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := countLines(t, filepath.Join(dir, "zlog.log"), "concurrent"); n != 16*75 {
+		t.Fatalf("expected %d lines in zlog.log, got %d", 16*75, n)
+	}
+	if n := countLines(t, filepath.Join(dir, "zlog2.log"), "concurrent"); n != 16*50 {
+		t.Fatalf("expected %d lines in zlog2.log, got %d", 16*50, n)
+	}
+}
+
+func BenchmarkWithPrefix(b *testing.B) {
+	z, _ := newDeriveZLog(b)
+	defer z.Close()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		z.WithPrefix("[bench]")
+	}
+}
+
+func BenchmarkWithName(b *testing.B) {
+	z, _ := newDeriveZLog(b)
+	defer z.Close()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		z.WithName("zlog2")
+	}
+}
+
+func BenchmarkInfoWithTrace(b *testing.B) {
+	z, _ := newDeriveZLog(b)
+	defer z.Close()
+	z.SetLevel("zlog", "warn")
+	ctx := trace.WithTraceContext(context.Background(), trace.NewTraceContext())
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		z.InfoWithTrace(ctx, "bench")
+	}
+}